@@ -0,0 +1,229 @@
+package handler
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"os"
+	"strings"
+)
+
+// ErrNoCredentials is returned by a RoleResolver when the request carries
+// no credentials.
+var ErrNoCredentials = errors.New("no credentials")
+
+// ErrInvalidCredentials is returned by a RoleResolver when the request
+// credentials are malformed, unknown or fail verification.
+var ErrInvalidCredentials = errors.New("invalid credentials")
+
+// ErrNoRole is answered by RoleMiddleware when the credentials are valid
+// but grant no role.
+var ErrNoRole = errors.New("credentials grant no role")
+
+// RoleResolver derives the roles of the caller from a request. It returns
+// an error wrapping ErrNoCredentials or ErrInvalidCredentials when the
+// caller cannot be identified.
+type RoleResolver interface {
+	ResolveRoles(r *http.Request) ([]string, error)
+}
+
+// RoleResolverFunc adapts a function to the RoleResolver interface.
+type RoleResolverFunc func(r *http.Request) ([]string, error)
+
+// ResolveRoles calls f(r).
+func (f RoleResolverFunc) ResolveRoles(r *http.Request) ([]string, error) {
+	return f(r)
+}
+
+// rolesKey is the context key of the resolved roles.
+type rolesKey struct{}
+
+// ContextWithRoles returns a copy of ctx carrying the resolved roles of
+// the caller.
+func ContextWithRoles(ctx context.Context, roles []string) context.Context {
+	return context.WithValue(ctx, rolesKey{}, roles)
+}
+
+// RolesFromContext returns the roles stored by ContextWithRoles and
+// whether any were stored.
+func RolesFromContext(ctx context.Context) ([]string, bool) {
+	roles, ok := ctx.Value(rolesKey{}).([]string)
+	return roles, ok
+}
+
+// RoleMiddleware resolves the roles of each request with resolver and
+// stores them in the request context for next. Requests that cannot be
+// identified are answered with 401, and requests granted no role with 403.
+// Handler uses the resolved roles in place of the "role" body field and
+// query parameter, so clients cannot pick their own role.
+func RoleMiddleware(resolver RoleResolver, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		roles, err := resolver.ResolveRoles(r)
+		if err != nil {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			writeError(w, http.StatusUnauthorized, err.Error())
+
+			return
+		}
+
+		if len(roles) == 0 {
+			writeError(w, http.StatusForbidden, ErrNoRole.Error())
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(ContextWithRoles(r.Context(), roles)))
+	})
+}
+
+// DefaultRole returns a RoleResolver that grants role to callers without
+// credentials and otherwise defers to resolver, e.g. to serve anonymous
+// users a restricted form.
+func DefaultRole(resolver RoleResolver, role string) RoleResolver {
+	return RoleResolverFunc(func(r *http.Request) ([]string, error) {
+		roles, err := resolver.ResolveRoles(r)
+		if errors.Is(err, ErrNoCredentials) {
+			return []string{role}, nil
+		}
+
+		return roles, err
+	})
+}
+
+// StaticTokenResolver resolves roles from bearer tokens listed in a fixed
+// map, e.g. for service accounts.
+type StaticTokenResolver struct {
+	tokens map[[sha256.Size]byte][]string
+}
+
+// NewStaticTokenResolver creates a resolver granting each token of tokens
+// its roles. Tokens are kept hashed and looked up by hash, so lookups do
+// not leak token prefixes through timing.
+func NewStaticTokenResolver(tokens map[string][]string) *StaticTokenResolver {
+	hashed := make(map[[sha256.Size]byte][]string, len(tokens))
+	for token, roles := range tokens {
+		hashed[sha256.Sum256([]byte(token))] = roles
+	}
+
+	return &StaticTokenResolver{tokens: hashed}
+}
+
+// LoadStaticTokenResolver reads a JSON file mapping tokens to roles, e.g.
+// {"s3cr3t": ["admin"]}, and creates a StaticTokenResolver.
+func LoadStaticTokenResolver(path string) (*StaticTokenResolver, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // path comes from the operator
+	if err != nil {
+		return nil, err
+	}
+
+	var tokens map[string][]string
+	if err := json.Unmarshal(data, &tokens); err != nil {
+		return nil, fmt.Errorf("parse tokens %s: %w", path, err)
+	}
+
+	return NewStaticTokenResolver(tokens), nil
+}
+
+// ResolveRoles implements RoleResolver.
+func (s *StaticTokenResolver) ResolveRoles(r *http.Request) ([]string, error) {
+	token, err := bearerToken(r)
+	if err != nil {
+		return nil, err
+	}
+
+	roles, ok := s.tokens[sha256.Sum256([]byte(token))]
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown token", ErrInvalidCredentials)
+	}
+
+	return roles, nil
+}
+
+// ErrNoTrustedProxy is returned by NewHeaderResolver without trusted
+// proxies.
+var ErrNoTrustedProxy = errors.New("role header needs at least one trusted proxy")
+
+// HeaderResolver reads the roles from a request header set by a trusted
+// authenticating proxy. The proxy must strip the header from client
+// requests; only requests from the trusted proxy addresses are accepted,
+// so clients cannot bypass it.
+type HeaderResolver struct {
+	header  string
+	proxies []netip.Prefix
+}
+
+// NewHeaderResolver creates a resolver reading comma-separated roles from
+// header, e.g. "X-User-Roles", of requests from the proxies. Requests from
+// other remote addresses are rejected. At least one proxy prefix is
+// required, else it returns ErrNoTrustedProxy.
+func NewHeaderResolver(header string, proxies ...netip.Prefix) (*HeaderResolver, error) {
+	if len(proxies) == 0 {
+		return nil, ErrNoTrustedProxy
+	}
+
+	return &HeaderResolver{header: header, proxies: proxies}, nil
+}
+
+// ResolveRoles implements RoleResolver.
+func (h *HeaderResolver) ResolveRoles(r *http.Request) ([]string, error) {
+	if !h.trusted(r.RemoteAddr) {
+		return nil, fmt.Errorf("%w: %s is not a trusted proxy", ErrInvalidCredentials, r.RemoteAddr)
+	}
+
+	value := r.Header.Get(h.header)
+	if value == "" {
+		return nil, fmt.Errorf("%w: missing %s header", ErrNoCredentials, h.header)
+	}
+
+	var roles []string
+
+	for _, role := range strings.Split(value, ",") {
+		if role = strings.TrimSpace(role); role != "" {
+			roles = append(roles, role)
+		}
+	}
+
+	return roles, nil
+}
+
+// trusted reports whether remoteAddr belongs to a trusted proxy.
+func (h *HeaderResolver) trusted(remoteAddr string) bool {
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		host = remoteAddr
+	}
+
+	addr, err := netip.ParseAddr(host)
+	if err != nil {
+		return false
+	}
+
+	addr = addr.Unmap()
+
+	for _, prefix := range h.proxies {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// bearerToken returns the token of an "Authorization: Bearer" header.
+func bearerToken(r *http.Request) (string, error) {
+	header := r.Header.Get("Authorization")
+	if header == "" {
+		return "", fmt.Errorf("%w: missing Authorization header", ErrNoCredentials)
+	}
+
+	scheme, token, ok := strings.Cut(header, " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") || strings.TrimSpace(token) == "" {
+		return "", fmt.Errorf("%w: expected a bearer token", ErrInvalidCredentials)
+	}
+
+	return strings.TrimSpace(token), nil
+}
@@ -0,0 +1,189 @@
+package handler_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	handler "github.com/holdemlab/ui-json-schema/api"
+	"github.com/holdemlab/ui-json-schema/schema"
+)
+
+// newAuthRequest creates a request with an Authorization header.
+func newAuthRequest(authorization string) *http.Request {
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	if authorization != "" {
+		r.Header.Set("Authorization", authorization)
+	}
+
+	return r
+}
+
+func TestStaticTokenResolver(t *testing.T) {
+	res := handler.NewStaticTokenResolver(map[string][]string{"s3cr3t": {"admin", "editor"}})
+
+	roles, err := res.ResolveRoles(newAuthRequest("Bearer s3cr3t"))
+	if err != nil || !reflect.DeepEqual(roles, []string{"admin", "editor"}) {
+		t.Errorf("got %v, %v", roles, err)
+	}
+
+	tests := []struct {
+		name          string
+		authorization string
+		expected      error
+	}{
+		{"missing", "", handler.ErrNoCredentials},
+		{"unknown", "Bearer guess", handler.ErrInvalidCredentials},
+		{"scheme", "Basic s3cr3t", handler.ErrInvalidCredentials},
+		{"empty token", "Bearer ", handler.ErrInvalidCredentials},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := res.ResolveRoles(newAuthRequest(tt.authorization)); !errors.Is(err, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, err)
+			}
+		})
+	}
+}
+
+func TestLoadStaticTokenResolver(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tokens.json")
+	if err := os.WriteFile(path, []byte(`{"t1": ["viewer"]}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := handler.LoadStaticTokenResolver(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if roles, err := res.ResolveRoles(newAuthRequest("bearer t1")); err != nil || !reflect.DeepEqual(roles, []string{"viewer"}) {
+		t.Errorf("got %v, %v", roles, err)
+	}
+
+	if err := os.WriteFile(path, []byte(`{"t1": "viewer"}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := handler.LoadStaticTokenResolver(path); err == nil {
+		t.Error("expected error for a malformed tokens file")
+	}
+}
+
+func TestHeaderResolver(t *testing.T) {
+	res, err := handler.NewHeaderResolver("X-User-Roles", netip.MustParsePrefix("10.0.0.0/8"), netip.MustParsePrefix("::1/128"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		remoteAddr string
+		header     string
+		roles      []string
+		expected   error
+	}{
+		{"trusted", "10.1.2.3:5000", "viewer, editor,", []string{"viewer", "editor"}, nil},
+		{"trusted ipv6", "[::1]:5000", "admin", []string{"admin"}, nil},
+		{"untrusted", "192.0.2.1:5000", "admin", nil, handler.ErrInvalidCredentials},
+		{"missing header", "10.1.2.3:5000", "", nil, handler.ErrNoCredentials},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r.RemoteAddr = tt.remoteAddr
+
+			if tt.header != "" {
+				r.Header.Set("X-User-Roles", tt.header)
+			}
+
+			roles, err := res.ResolveRoles(r)
+			if !errors.Is(err, tt.expected) || !reflect.DeepEqual(roles, tt.roles) {
+				t.Errorf("got %v, %v; want %v, %v", roles, err, tt.roles, tt.expected)
+			}
+		})
+	}
+
+	// Trusting every remote address is not allowed.
+	if _, err := handler.NewHeaderResolver("X-Role"); !errors.Is(err, handler.ErrNoTrustedProxy) {
+		t.Errorf("expected ErrNoTrustedProxy, got %v", err)
+	}
+}
+
+func TestRoleMiddleware(t *testing.T) {
+	reg := handler.NewRegistry()
+	reg.Register("User", testUser{})
+
+	opts := schema.DefaultOptions()
+	opts.RolePermissions = map[string]schema.FieldPermissions{
+		"viewer": {"age": schema.AccessHidden},
+	}
+
+	h := handler.NewHandlerWithOptions(reg, opts)
+	res := handler.NewStaticTokenResolver(map[string][]string{"admin": {"admin"}, "viewer": {"viewer"}, "none": nil})
+	srv := handler.RoleMiddleware(res, http.HandlerFunc(h.GenerateHandler))
+
+	tests := []struct {
+		name          string
+		authorization string
+		body          string
+		status        int
+		hasAge        bool
+	}{
+		{"viewer", "Bearer viewer", `{"type":"User"}`, http.StatusOK, false},
+		{"client role ignored", "Bearer admin", `{"type":"User","role":"viewer"}`, http.StatusOK, true},
+		{"client cannot escalate", "Bearer viewer", `{"type":"User","role":"admin"}`, http.StatusOK, false},
+		{"no credentials", "", `{"type":"User"}`, http.StatusUnauthorized, false},
+		{"invalid token", "Bearer nope", `{"type":"User"}`, http.StatusUnauthorized, false},
+		{"no role", "Bearer none", `{"type":"User"}`, http.StatusForbidden, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, endpointPath+"?role=viewer", strings.NewReader(tt.body))
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+
+			rr := httptest.NewRecorder()
+			srv.ServeHTTP(rr, req)
+
+			if rr.Code != tt.status {
+				t.Fatalf("expected %d, got %d: %s", tt.status, rr.Code, rr.Body.String())
+			}
+
+			if rr.Code != http.StatusOK {
+				assertErrorResponse(t, rr)
+				return
+			}
+
+			if hasAge := strings.Contains(rr.Body.String(), `"age"`); hasAge != tt.hasAge {
+				t.Errorf("expected age present=%v, got %s", tt.hasAge, rr.Body.String())
+			}
+		})
+	}
+}
+
+func TestDefaultRole(t *testing.T) {
+	res := handler.DefaultRole(handler.NewStaticTokenResolver(map[string][]string{"t": {"admin"}}), "guest")
+
+	if roles, err := res.ResolveRoles(newAuthRequest("")); err != nil || !reflect.DeepEqual(roles, []string{"guest"}) {
+		t.Errorf("expected guest role, got %v, %v", roles, err)
+	}
+
+	if roles, err := res.ResolveRoles(newAuthRequest("Bearer t")); err != nil || !reflect.DeepEqual(roles, []string{"admin"}) {
+		t.Errorf("expected admin role, got %v, %v", roles, err)
+	}
+
+	if _, err := res.ResolveRoles(newAuthRequest("Bearer x")); !errors.Is(err, handler.ErrInvalidCredentials) {
+		t.Errorf("expected ErrInvalidCredentials, got %v", err)
+	}
+}
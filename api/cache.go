@@ -0,0 +1,263 @@
+package handler
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/holdemlab/ui-json-schema/parser"
+	"github.com/holdemlab/ui-json-schema/schema"
+)
+
+// defaultCacheSize is the number of responses cached by default.
+const defaultCacheSize = 256
+
+// defaultCacheControl lets clients store type responses but revalidate
+// them with If-None-Match, which is cheap and keeps role-specific forms
+// out of shared caches.
+const defaultCacheControl = "private, no-cache"
+
+// roleCacheControl replaces the configured Cache-Control of responses
+// generated for roles. The roles come from credentials or headers that
+// caches do not key on, so a stored response could reach another role.
+const roleCacheControl = "private, no-store"
+
+// CacheOptions configures the HTTP caching of the schemas of registered
+// types.
+type CacheOptions struct {
+	// Size is the number of serialized responses kept in memory, least
+	// recently used first out. Zero disables the in-process cache; ETags
+	// and conditional requests still work.
+	Size int
+	// CacheControl is the Cache-Control header of type responses. Responses
+	// generated for roles always get "private, no-store".
+	CacheControl string
+}
+
+// DefaultCacheOptions returns CacheOptions with a 256 entry cache and
+// "private, no-cache".
+func DefaultCacheOptions() CacheOptions {
+	return CacheOptions{Size: defaultCacheSize, CacheControl: defaultCacheControl}
+}
+
+// CacheStats reports the activity of the response cache.
+type CacheStats struct {
+	// Hits counts responses served from the cache.
+	Hits uint64 `json:"hits"`
+	// Misses counts responses that had to be generated.
+	Misses uint64 `json:"misses"`
+	// Evictions counts entries dropped to make room.
+	Evictions uint64 `json:"evictions"`
+	// NotModified counts conditional requests answered with 304.
+	NotModified uint64 `json:"notModified"`
+	// Entries is the number of cached responses.
+	Entries int `json:"entries"`
+	// Size is the capacity of the cache.
+	Size int `json:"size"`
+	// HitRate is Hits / (Hits + Misses), or 0 before any lookup.
+	HitRate float64 `json:"hitRate"`
+}
+
+// cachedResponse is a serialized response body with its ETag.
+type cachedResponse struct {
+	key  string
+	body []byte
+	etag string
+}
+
+// responseCache is an LRU of serialized responses, safe for concurrent use.
+type responseCache struct {
+	mu      sync.Mutex
+	size    int
+	entries map[string]*list.Element
+	order   *list.List
+	stats   CacheStats
+}
+
+// newResponseCache creates a cache holding up to size responses.
+func newResponseCache(size int) *responseCache {
+	return &responseCache{size: size, entries: make(map[string]*list.Element), order: list.New()}
+}
+
+// get returns the response cached under key.
+func (c *responseCache) get(key string) (*cachedResponse, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.entries[key]
+	if !ok {
+		c.stats.Misses++
+		return nil, false
+	}
+
+	c.stats.Hits++
+	c.order.MoveToFront(el)
+
+	resp, _ := el.Value.(*cachedResponse)
+
+	return resp, true
+}
+
+// add caches resp, evicting the least recently used responses beyond the
+// capacity.
+func (c *responseCache) add(resp *cachedResponse) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.size <= 0 {
+		return
+	}
+
+	if el, ok := c.entries[resp.key]; ok {
+		el.Value = resp
+		c.order.MoveToFront(el)
+
+		return
+	}
+
+	c.entries[resp.key] = c.order.PushFront(resp)
+
+	for c.order.Len() > c.size {
+		oldest, _ := c.order.Remove(c.order.Back()).(*cachedResponse)
+		delete(c.entries, oldest.key)
+		c.stats.Evictions++
+	}
+}
+
+// notModified records a 304 answer.
+func (c *responseCache) notModified() {
+	c.mu.Lock()
+	c.stats.NotModified++
+	c.mu.Unlock()
+}
+
+// snapshot returns the current statistics.
+func (c *responseCache) snapshot() CacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	stats := c.stats
+	stats.Entries = c.order.Len()
+	stats.Size = c.size
+
+	if lookups := stats.Hits + stats.Misses; lookups > 0 {
+		stats.HitRate = float64(stats.Hits) / float64(lookups)
+	}
+
+	return stats
+}
+
+// SetCacheOptions replaces the caching configuration of h and empties its
+// cache. It is meant to be called before h serves requests.
+func (h *Handler) SetCacheOptions(o CacheOptions) {
+	h.cacheOpts = o
+	h.cache = newResponseCache(o.Size)
+}
+
+// CacheStats returns the activity of the response cache of h.
+func (h *Handler) CacheStats() CacheStats {
+	return h.cache.snapshot()
+}
+
+// CacheStatsHandler handles GET /schema/cache.
+// It returns the CacheStats of the handler, e.g. for monitoring.
+func (h *Handler) CacheStatsHandler(w http.ResponseWriter, r *http.Request) {
+	if !allowGet(w, r) {
+		return
+	}
+
+	writeJSON(w, http.StatusOK, h.CacheStats())
+}
+
+// cacheKey identifies a response of the given kind for type name. It
+// covers the registry and variant revisions and every per-request option,
+// so equal keys always serialize to the same response.
+func (h *Handler) cacheKey(kind, name string, opts schema.Options) string {
+	roles := append([]string{opts.Role}, opts.Roles...)
+	sort.Strings(roles)
+	roles = slices.Compact(roles)
+
+	renderers := make([]string, 0, len(opts.Renderers))
+	for scope, renderer := range opts.Renderers {
+		renderers = append(renderers, scope+"="+renderer)
+	}
+
+	sort.Strings(renderers)
+
+	return strings.Join([]string{
+		kind, name, strconv.FormatUint(h.registry.revision(), 10),
+		strconv.FormatUint(parser.VariantsRevision(), 10),
+		opts.Locale, strings.Join(roles, ","), opts.Draft, strings.Join(renderers, ","),
+	}, "\x00")
+}
+
+// serveCached writes the response of the given kind for type name, taken
+// from the cache or built and cached. It sets ETag and Cache-Control and
+// answers a matching If-None-Match of a GET or HEAD request with 304.
+// Responses for roles are marked "private, no-store" whatever the
+// configuration says.
+func (h *Handler) serveCached(w http.ResponseWriter, r *http.Request, kind, name string, opts schema.Options, build func() (any, error)) {
+	key := h.cacheKey(kind, name, opts)
+
+	resp, ok := h.cache.get(key)
+	if !ok {
+		v, err := build()
+		if err != nil {
+			writeGenerationError(w, err)
+			return
+		}
+
+		body, err := json.Marshal(v)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		sum := sha256.Sum256(body)
+		resp = &cachedResponse{key: key, body: append(body, '\n'), etag: `"` + hex.EncodeToString(sum[:16]) + `"`}
+		h.cache.add(resp)
+	}
+
+	header := w.Header()
+	header.Set("ETag", resp.etag)
+	header.Set("Vary", "Accept-Language")
+
+	switch {
+	case opts.Role != "" || len(opts.Roles) > 0:
+		header.Set("Cache-Control", roleCacheControl)
+	case h.cacheOpts.CacheControl != "":
+		header.Set("Cache-Control", h.cacheOpts.CacheControl)
+	}
+
+	if (r.Method == http.MethodGet || r.Method == http.MethodHead) && etagMatches(r.Header.Get("If-None-Match"), resp.etag) {
+		h.cache.notModified()
+		w.WriteHeader(http.StatusNotModified)
+
+		return
+	}
+
+	header.Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	_, _ = w.Write(resp.body) //nolint:errcheck // best-effort response write
+}
+
+// etagMatches reports whether an If-None-Match header lists etag, using
+// the weak comparison of RFC 9110.
+func etagMatches(header, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,277 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	handler "github.com/holdemlab/ui-json-schema/api"
+	"github.com/holdemlab/ui-json-schema/parser"
+	"github.com/holdemlab/ui-json-schema/schema"
+)
+
+// newCacheMux mounts the type routes of h.
+func newCacheMux(h *handler.Handler) *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/schema/generate", h.GenerateHandler)
+	mux.HandleFunc("GET /schema/types/{name}", h.TypeHandler)
+	mux.HandleFunc("GET /schema/types/{name}/uischema", h.UISchemaHandler)
+	mux.HandleFunc("GET /schema/cache", h.CacheStatsHandler)
+
+	return mux
+}
+
+// doConditional sends a request with an If-None-Match header.
+func doConditional(mux http.Handler, method, path, ifNoneMatch string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, nil)
+	if ifNoneMatch != "" {
+		req.Header.Set("If-None-Match", ifNoneMatch)
+	}
+
+	rr := httptest.NewRecorder()
+	mux.ServeHTTP(rr, req)
+
+	return rr
+}
+
+func TestHandler_ETag(t *testing.T) {
+	reg := handler.NewRegistry()
+	reg.Register("User", testUser{})
+
+	mux := newCacheMux(handler.NewHandler(reg))
+
+	rr := doGet(mux, "/schema/types/User")
+	etag := rr.Header().Get("ETag")
+
+	if rr.Code != http.StatusOK || !strings.HasPrefix(etag, `"`) || rr.Header().Get("Cache-Control") != "private, no-cache" {
+		t.Fatalf("unexpected response %d, headers %v", rr.Code, rr.Header())
+	}
+
+	if again := doGet(mux, "/schema/types/User"); again.Header().Get("ETag") != etag || again.Body.String() != rr.Body.String() {
+		t.Error("expected a stable ETag and body")
+	}
+
+	tests := []struct {
+		name        string
+		method      string
+		ifNoneMatch string
+		status      int
+	}{
+		{"match", http.MethodGet, etag, http.StatusNotModified},
+		{"weak match in list", http.MethodGet, `"other", W/` + etag, http.StatusNotModified},
+		{"wildcard", http.MethodGet, "*", http.StatusNotModified},
+		{"head", http.MethodHead, etag, http.StatusNotModified},
+		{"stale", http.MethodGet, `"other"`, http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := doConditional(mux, tt.method, "/schema/types/User", tt.ifNoneMatch)
+			if rr.Code != tt.status {
+				t.Fatalf("expected %d, got %d", tt.status, rr.Code)
+			}
+
+			if rr.Header().Get("ETag") != etag {
+				t.Errorf("expected ETag %s, got %s", etag, rr.Header().Get("ETag"))
+			}
+
+			if tt.status == http.StatusNotModified && rr.Body.Len() != 0 {
+				t.Errorf("expected an empty 304 body, got %s", rr.Body.String())
+			}
+		})
+	}
+
+	// POST responses carry an ETag but are never answered with 304.
+	req := httptest.NewRequest(http.MethodPost, endpointPath, strings.NewReader(`{"type":"User"}`))
+	req.Header.Set("If-None-Match", "*")
+
+	post := httptest.NewRecorder()
+	mux.ServeHTTP(post, req)
+
+	if post.Code != http.StatusOK || post.Header().Get("ETag") == "" {
+		t.Errorf("expected 200 with an ETag, got %d, headers %v", post.Code, post.Header())
+	}
+}
+
+func TestHandler_ETagVaries(t *testing.T) {
+	reg := handler.NewRegistry()
+	reg.Register("User", testUser{})
+
+	opts := schema.DefaultOptions()
+	opts.RolePermissions = map[string]schema.FieldPermissions{"viewer": {"age": schema.AccessHidden}}
+
+	h := handler.NewHandlerWithOptions(reg, opts)
+	h.SetClientRoles(true)
+
+	mux := newCacheMux(h)
+	etag := func(path string) string { return doGet(mux, path).Header().Get("ETag") }
+
+	base := etag("/schema/types/User")
+
+	for _, path := range []string{
+		"/schema/types/User?role=viewer",
+		"/schema/types/User?draft=2019-09",
+		"/schema/types/User?renderer=%23/properties/name=x",
+		"/schema/types/User/uischema",
+	} {
+		if etag(path) == base {
+			t.Errorf("%s: expected a different ETag", path)
+		}
+	}
+
+	// The ETag hashes the content: the draft does not change the UI Schema.
+	if etag("/schema/types/User/uischema?draft=2019-09") != etag("/schema/types/User/uischema") {
+		t.Error("expected equal ETags for equal UI Schemas")
+	}
+
+	// Changing the registration invalidates cached responses.
+	reg.RegisterTemplate("User", &schema.UISchemaTemplate{Elements: []*schema.UISchemaTemplate{{Field: "email"}}})
+
+	if etag("/schema/types/User") == base {
+		t.Error("expected a new ETag after the template changed")
+	}
+}
+
+func TestHandler_CacheStats(t *testing.T) {
+	reg := handler.NewRegistry()
+	reg.Register("User", testUser{})
+	reg.Register("Other", testUser{})
+
+	h := handler.NewHandler(reg)
+	h.SetCacheOptions(handler.CacheOptions{Size: 1, CacheControl: "max-age=60"})
+
+	mux := newCacheMux(h)
+
+	if rr := doGet(mux, "/schema/types/User"); rr.Header().Get("Cache-Control") != "max-age=60" {
+		t.Errorf("expected the configured Cache-Control, got %q", rr.Header().Get("Cache-Control"))
+	}
+
+	doGet(mux, "/schema/types/User")
+	etag := doGet(mux, "/schema/types/User").Header().Get("ETag")
+	doConditional(mux, http.MethodGet, "/schema/types/User", etag)
+	doGet(mux, "/schema/types/Other")
+	doGet(mux, "/schema/types/Nope")
+
+	expected := handler.CacheStats{Hits: 3, Misses: 3, Evictions: 1, NotModified: 1, Entries: 1, Size: 1, HitRate: 0.5}
+	if stats := h.CacheStats(); stats != expected {
+		t.Errorf("got %+v, want %+v", stats, expected)
+	}
+
+	rr := doGet(mux, "/schema/cache")
+
+	var stats handler.CacheStats
+	if err := json.Unmarshal(rr.Body.Bytes(), &stats); err != nil || stats != expected {
+		t.Errorf("unexpected stats response %s", rr.Body.String())
+	}
+}
+
+func TestHandler_CacheDisabled(t *testing.T) {
+	reg := handler.NewRegistry()
+	reg.Register("User", testUser{})
+
+	h := handler.NewHandler(reg)
+	h.SetCacheOptions(handler.CacheOptions{})
+
+	mux := newCacheMux(h)
+
+	rr := doGet(mux, "/schema/types/User")
+	if rr.Header().Get("Cache-Control") != "" {
+		t.Errorf("expected no Cache-Control, got %q", rr.Header().Get("Cache-Control"))
+	}
+
+	if again := doConditional(mux, http.MethodGet, "/schema/types/User", rr.Header().Get("ETag")); again.Code != http.StatusNotModified {
+		t.Errorf("expected 304 without the cache, got %d", again.Code)
+	}
+
+	if stats := h.CacheStats(); stats.Entries != 0 || stats.Hits != 0 || stats.Misses != 2 {
+		t.Errorf("expected nothing cached, got %+v", stats)
+	}
+}
+
+func TestHandler_RoleResponsesNotStored(t *testing.T) {
+	reg := handler.NewRegistry()
+	reg.Register("User", testUser{})
+
+	opts := schema.DefaultOptions()
+	opts.RolePermissions = map[string]schema.FieldPermissions{"viewer": {"age": schema.AccessHidden}}
+
+	h := handler.NewHandlerWithOptions(reg, opts)
+	h.SetCacheOptions(handler.CacheOptions{Size: 8, CacheControl: "public, max-age=60"})
+
+	mux := newCacheMux(h)
+
+	// Both requests use the same URL, as behind RoleMiddleware.
+	get := func(role string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/schema/types/User", nil)
+		req = req.WithContext(handler.ContextWithRoles(req.Context(), []string{role}))
+
+		rr := httptest.NewRecorder()
+		mux.ServeHTTP(rr, req)
+
+		return rr
+	}
+
+	admin, viewer := get("admin"), get("viewer")
+
+	if admin.Header().Get("ETag") == viewer.Header().Get("ETag") || admin.Body.String() == viewer.Body.String() {
+		t.Error("expected different responses per role")
+	}
+
+	for _, rr := range []*httptest.ResponseRecorder{admin, viewer} {
+		if cc := rr.Header().Get("Cache-Control"); cc != "private, no-store" {
+			t.Errorf("expected private, no-store for a role, got %q", cc)
+		}
+	}
+
+	if cc := doGet(mux, "/schema/types/User").Header().Get("Cache-Control"); cc != "public, max-age=60" {
+		t.Errorf("expected the configured Cache-Control without roles, got %q", cc)
+	}
+}
+
+type cacheShape interface {
+	cacheShape()
+}
+
+type cacheCircle struct {
+	Radius float64 `json:"radius"`
+}
+
+func (cacheCircle) cacheShape() {}
+
+type cacheSquare struct {
+	Side float64 `json:"side"`
+}
+
+func (cacheSquare) cacheShape() {}
+
+type cacheDrawing struct {
+	Shape cacheShape `json:"shape"`
+}
+
+func TestHandler_CacheFollowsVariants(t *testing.T) {
+	register := func(variants ...parser.Variant) {
+		t.Helper()
+
+		if err := parser.RegisterVariants((*cacheShape)(nil), "kind", variants...); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	register(parser.Variant{Value: "circle", Type: cacheCircle{}})
+
+	reg := handler.NewRegistry()
+	reg.Register("Drawing", cacheDrawing{})
+
+	mux := newCacheMux(handler.NewHandler(reg))
+
+	before := doGet(mux, "/schema/types/Drawing")
+
+	register(parser.Variant{Value: "circle", Type: cacheCircle{}}, parser.Variant{Value: "square", Type: cacheSquare{}})
+
+	after := doGet(mux, "/schema/types/Drawing")
+	if after.Header().Get("ETag") == before.Header().Get("ETag") || !strings.Contains(after.Body.String(), `"square"`) {
+		t.Errorf("expected the new variant after RegisterVariants, got %s", after.Body.String())
+	}
+}
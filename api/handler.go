@@ -13,12 +13,17 @@ import (
 // maxRequestBody limits the request body size (2 MB).
 const maxRequestBody = 2 << 20
 
+// pathName is the path wildcard holding the type name in the per-type
+// routes, e.g. "GET /schema/types/{name}".
+const pathName = "name"
+
 // generateRequest represents the incoming request for schema generation.
 type generateRequest struct {
 	// Type is the registered Go type name. If set, JSON payload is ignored.
 	Type string `json:"type,omitempty"`
 	// Data is a raw JSON object to generate schemas from.
 	Data json.RawMessage `json:"data,omitempty"`
+	requestOptions
 }
 
 // generateResponse is the response containing both schemas.
@@ -27,6 +32,33 @@ type generateResponse struct {
 	UISchema *schema.UISchemaElement `json:"uischema"`
 }
 
+// validateRequest represents the incoming request for data validation.
+type validateRequest struct {
+	// Type is the registered Go type name whose JSON Schema applies.
+	Type string `json:"type"`
+	// Data is the submitted JSON value to validate.
+	Data json.RawMessage `json:"data"`
+	requestOptions
+}
+
+// validateResponse reports the result of a validation.
+type validateResponse struct {
+	Valid  bool                     `json:"valid"`
+	Errors []schema.ValidationError `json:"errors"`
+}
+
+// typesResponse lists the registered types.
+type typesResponse struct {
+	Types []TypeInfo `json:"types"`
+}
+
+// typeResponse describes a registered type with both its schemas.
+type typeResponse struct {
+	TypeInfo
+	Schema   *schema.JSONSchema      `json:"schema"`
+	UISchema *schema.UISchemaElement `json:"uischema"`
+}
+
 // errorResponse is a JSON error response body.
 type errorResponse struct {
 	Error string `json:"error"`
@@ -34,83 +66,298 @@ type errorResponse struct {
 
 // Handler provides HTTP handlers for the schema generation API.
 type Handler struct {
-	registry *Registry
+	registry    *Registry
+	opts        schema.Options
+	cache       *responseCache
+	cacheOpts   CacheOptions
+	clientRoles bool
 }
 
-// NewHandler creates a new Handler with the given type registry.
+// NewHandler creates a new Handler with the given type registry and
+// default options.
 func NewHandler(registry *Registry) *Handler {
-	return &Handler{registry: registry}
+	return NewHandlerWithOptions(registry, schema.DefaultOptions())
+}
+
+// NewHandlerWithOptions creates a new Handler that generates schemas with
+// opts, e.g. to configure a Translator and RolePermissions. Requests may
+// override the locale, draft and renderers (see the "locale", "draft" and
+// "renderers" body fields, the matching query parameters and
+// Accept-Language); roles come from RoleMiddleware, or from the "role"
+// field after SetClientRoles. opts.Template is ignored; templates
+// come from the registry. Responses for registered types are cached with
+// DefaultCacheOptions (see SetCacheOptions).
+func NewHandlerWithOptions(registry *Registry, opts schema.Options) *Handler {
+	h := &Handler{registry: registry, opts: opts}
+	h.SetCacheOptions(DefaultCacheOptions())
+
+	return h
 }
 
 // GenerateHandler handles POST /schema/generate.
 // It accepts a JSON body with either a "type" field (registered Go type)
-// or a "data" field (raw JSON object) and returns both schemas.
+// or a "data" field (raw JSON object) and returns both schemas. Responses
+// for registered types are cached and carry an ETag.
 func (h *Handler) GenerateHandler(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPost {
-		writeError(w, http.StatusMethodNotAllowed, "method not allowed, use POST")
+	var req generateRequest
+	if !readRequest(w, r, &req) {
 		return
 	}
 
-	body, err := io.ReadAll(io.LimitReader(r.Body, maxRequestBody))
+	opts, err := h.options(r, req.requestOptions)
 	if err != nil {
-		writeError(w, http.StatusBadRequest, "failed to read request body")
+		writeError(w, http.StatusBadRequest, err.Error())
 		return
 	}
-	defer r.Body.Close() //nolint:errcheck // closing body, error is irrelevant
 
-	if len(body) == 0 {
-		writeError(w, http.StatusBadRequest, "request body is empty")
+	if req.Type != "" {
+		h.serveCached(w, r, "generate", req.Type, opts, func() (any, error) {
+			return h.generateFromType(req.Type, opts)
+		})
+
 		return
 	}
 
-	var req generateRequest
-	if unmarshalErr := json.Unmarshal(body, &req); unmarshalErr != nil {
-		writeError(w, http.StatusBadRequest, "invalid JSON in request body")
+	if len(req.Data) == 0 {
+		writeError(w, http.StatusBadRequest, "request must contain \"type\" or \"data\" field")
 		return
 	}
 
-	var resp generateResponse
+	resp, err := h.generateFromData(req.Data, opts)
+	if err != nil {
+		writeGenerationError(w, err)
+		return
+	}
 
-	switch {
-	case req.Type != "":
-		resp, err = h.generateFromType(req.Type)
-	case len(req.Data) > 0:
-		resp, err = h.generateFromData(req.Data)
-	default:
-		writeError(w, http.StatusBadRequest, "request must contain \"type\" or \"data\" field")
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// ValidateHandler handles POST /schema/validate.
+// It accepts a JSON body with a "type" field (registered Go type) and a
+// "data" field, validates data against the type's JSON Schema and returns
+// {"valid": bool, "errors": [...]} with JSON Pointer instance paths. With
+// active roles, values they may not write are reported as "readOnly"
+// errors. Invalid data is a successful validation and answers 200.
+func (h *Handler) ValidateHandler(w http.ResponseWriter, r *http.Request) {
+	var req validateRequest
+	if !readRequest(w, r, &req) {
+		return
+	}
+
+	if req.Type == "" || len(req.Data) == 0 {
+		writeError(w, http.StatusBadRequest, "request must contain \"type\" and \"data\" fields")
+		return
+	}
+
+	opts, err := h.options(r, req.requestOptions)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	v, err := h.registry.Lookup(req.Type)
+	if err != nil {
+		writeGenerationError(w, err)
 		return
 	}
 
+	jsonSchema, err := parser.GenerateJSONSchemaWithOptions(v, opts)
 	if err != nil {
-		status := http.StatusInternalServerError
-		if errors.Is(err, parser.ErrInvalidJSON) || errors.Is(err, parser.ErrNotJSONObject) {
-			status = http.StatusBadRequest
+		writeGenerationError(w, err)
+		return
+	}
+
+	errs := schema.Validate(jsonSchema, req.Data)
+
+	forbidden, err := writeErrors(v, req.Data, opts)
+	if err != nil {
+		writeGenerationError(w, err)
+		return
+	}
+
+	errs = append(errs, forbidden...)
+	if errs == nil {
+		errs = []schema.ValidationError{}
+	}
+
+	writeJSON(w, http.StatusOK, validateResponse{Valid: len(errs) == 0, Errors: errs})
+}
+
+// writeErrors reports the values of data that the active roles of opts, or
+// a caller without a role, may not write (see parser.FilterWrite) as
+// "readOnly" validation errors. Data that is not an object is left to
+// schema.Validate.
+func writeErrors(v any, data json.RawMessage, opts schema.Options) ([]schema.ValidationError, error) {
+	var obj map[string]any
+	if json.Unmarshal(data, &obj) != nil {
+		return nil, nil
+	}
+
+	_, removed, err := parser.FilterWrite(v, obj, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	errs := make([]schema.ValidationError, 0, len(removed))
+	for _, ptr := range removed {
+		errs = append(errs, schema.ValidationError{InstancePath: ptr, Keyword: "readOnly", Message: "must not be written"})
+	}
+
+	return errs, nil
+}
+
+// TypesHandler handles GET /schema/types.
+// It lists the registered types with their titles and descriptions.
+func (h *Handler) TypesHandler(w http.ResponseWriter, r *http.Request) {
+	if !allowGet(w, r) {
+		return
+	}
+
+	writeJSON(w, http.StatusOK, typesResponse{Types: h.registry.Infos()})
+}
+
+// TypeHandler handles GET /schema/types/{name}.
+// It returns the listing entry of the type with both its schemas. Like the
+// other type routes it is cached and answers If-None-Match with 304.
+func (h *Handler) TypeHandler(w http.ResponseWriter, r *http.Request) {
+	name, opts, ok := h.typeRequest(w, r)
+	if !ok {
+		return
+	}
+
+	h.serveCached(w, r, "type", name, opts, func() (any, error) {
+		info, err := h.registry.Info(name)
+		if err != nil {
+			return nil, err
 		}
-		// Check for registry lookup errors (type not found).
-		if isNotFoundError(err) {
-			status = http.StatusNotFound
+
+		resp, err := h.generateFromType(name, opts)
+		if err != nil {
+			return nil, err
 		}
 
-		writeError(w, status, err.Error())
+		return typeResponse{TypeInfo: info, Schema: resp.Schema, UISchema: resp.UISchema}, nil
+	})
+}
+
+// JSONSchemaHandler handles GET /schema/types/{name}/jsonschema.
+// It returns the JSON Schema of the type as the response body.
+func (h *Handler) JSONSchemaHandler(w http.ResponseWriter, r *http.Request) {
+	name, opts, ok := h.typeRequest(w, r)
+	if !ok {
 		return
 	}
 
-	writeJSON(w, http.StatusOK, resp)
+	h.serveCached(w, r, "jsonschema", name, opts, func() (any, error) {
+		resp, err := h.generateFromType(name, opts)
+		return resp.Schema, err
+	})
+}
+
+// UISchemaHandler handles GET /schema/types/{name}/uischema.
+// It returns the UI Schema of the type as the response body.
+func (h *Handler) UISchemaHandler(w http.ResponseWriter, r *http.Request) {
+	name, opts, ok := h.typeRequest(w, r)
+	if !ok {
+		return
+	}
+
+	h.serveCached(w, r, "uischema", name, opts, func() (any, error) {
+		resp, err := h.generateFromType(name, opts)
+		return resp.UISchema, err
+	})
+}
+
+// allowGet rejects methods other than GET and HEAD. On failure it writes
+// the error response and returns false.
+func allowGet(w http.ResponseWriter, r *http.Request) bool {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed, use GET")
+		return false
+	}
+
+	return true
+}
+
+// typeRequest returns the type name path value and the options of a GET
+// request. On failure it writes the error response and returns false.
+func (h *Handler) typeRequest(w http.ResponseWriter, r *http.Request) (string, schema.Options, bool) {
+	if !allowGet(w, r) {
+		return "", schema.Options{}, false
+	}
+
+	name := r.PathValue(pathName)
+	if name == "" {
+		writeError(w, http.StatusBadRequest, "missing type name in path")
+		return "", schema.Options{}, false
+	}
+
+	opts, err := h.options(r, requestOptions{})
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return "", schema.Options{}, false
+	}
+
+	return name, opts, true
+}
+
+// readRequest reads a POST request body as JSON into req. On failure it
+// writes the error response and returns false.
+func readRequest(w http.ResponseWriter, r *http.Request, req any) bool {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed, use POST")
+		return false
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxRequestBody))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "failed to read request body")
+		return false
+	}
+	defer r.Body.Close() //nolint:errcheck // closing body, error is irrelevant
+
+	if len(body) == 0 {
+		writeError(w, http.StatusBadRequest, "request body is empty")
+		return false
+	}
+
+	if err := json.Unmarshal(body, req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON in request body")
+		return false
+	}
+
+	return true
+}
+
+// writeGenerationError writes the error response for a failed registry
+// lookup or schema generation.
+func writeGenerationError(w http.ResponseWriter, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, ErrTypeNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, parser.ErrInvalidJSON) || errors.Is(err, parser.ErrNotJSONObject):
+		status = http.StatusBadRequest
+	}
+
+	writeError(w, status, err.Error())
 }
 
 // generateFromType generates schemas from a registered Go type name.
-func (h *Handler) generateFromType(typeName string) (generateResponse, error) {
+func (h *Handler) generateFromType(typeName string, opts schema.Options) (generateResponse, error) {
 	v, err := h.registry.Lookup(typeName)
 	if err != nil {
 		return generateResponse{}, err
 	}
 
-	jsonSchema, err := parser.GenerateJSONSchema(v)
+	jsonSchema, err := parser.GenerateJSONSchemaWithOptions(v, opts)
 	if err != nil {
 		return generateResponse{}, err
 	}
 
-	uiSchema, err := parser.GenerateUISchema(v)
+	opts.Template = h.registry.Template(typeName)
+
+	uiSchema, err := parser.GenerateUISchemaWithOptions(v, opts)
 	if err != nil {
 		return generateResponse{}, err
 	}
@@ -119,8 +366,8 @@ func (h *Handler) generateFromType(typeName string) (generateResponse, error) {
 }
 
 // generateFromData generates schemas from raw JSON data.
-func (h *Handler) generateFromData(data json.RawMessage) (generateResponse, error) {
-	jsonSchema, uiSchema, err := parser.GenerateFromJSON(data)
+func (h *Handler) generateFromData(data json.RawMessage, opts schema.Options) (generateResponse, error) {
+	jsonSchema, uiSchema, err := parser.GenerateFromJSONWithOptions(data, opts)
 	if err != nil {
 		return generateResponse{}, err
 	}
@@ -140,8 +387,3 @@ func writeJSON(w http.ResponseWriter, status int, v any) {
 func writeError(w http.ResponseWriter, status int, message string) {
 	writeJSON(w, status, errorResponse{Error: message})
 }
-
-// isNotFoundError checks if an error is a "not found" registry error.
-func isNotFoundError(err error) bool {
-	return err != nil && len(err.Error()) > 4 && err.Error()[len(err.Error())-len("not found in registry"):] == "not found in registry"
-}
@@ -4,10 +4,12 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
 	"strings"
 	"testing"
 
 	handler "github.com/holdemlab/ui-json-schema/api"
+	"github.com/holdemlab/ui-json-schema/schema"
 )
 
 const (
@@ -368,3 +370,468 @@ func assertErrorResponse(t *testing.T, rr *httptest.ResponseRecorder) {
 		t.Error("expected 'error' field in error response")
 	}
 }
+
+func TestHandler_GenerateFromType_Template(t *testing.T) {
+	reg := handler.NewRegistry()
+	reg.Register("User", testUser{})
+	reg.RegisterTemplate("User", &schema.UISchemaTemplate{
+		Elements: []*schema.UISchemaTemplate{{Field: "age"}, {Field: "name"}},
+	})
+
+	rr := doPost(t, handler.NewHandler(reg), `{"type":"User"}`)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+
+	var resp struct {
+		UISchema schema.UISchemaElement `json:"uischema"`
+	}
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("invalid JSON response: %v", err)
+	}
+
+	els := resp.UISchema.Elements
+	if len(els) != 3 || els[0].Scope != "#/properties/age" || els[1].Scope != "#/properties/name" {
+		t.Errorf("expected template order age, name, email; got %+v", els)
+	}
+}
+
+// doValidate sends a POST request to the validate endpoint.
+func doValidate(t *testing.T, h *handler.Handler, method, body string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	req := httptest.NewRequest(method, "/schema/validate", strings.NewReader(body))
+	req.Header.Set("Content-Type", contentTypeJSON)
+
+	rr := httptest.NewRecorder()
+	h.ValidateHandler(rr, req)
+
+	return rr
+}
+
+type validateResult struct {
+	Valid  bool                     `json:"valid"`
+	Errors []schema.ValidationError `json:"errors"`
+}
+
+func TestHandler_Validate(t *testing.T) {
+	h := newTestHandler()
+
+	tests := []struct {
+		name   string
+		body   string
+		valid  bool
+		errors []string
+	}{
+		{"valid", `{"type":"User","data":{"name":"Ann","email":"ann@example.com","age":30}}`, true, nil},
+		{"invalid", `{"type":"User","data":{"email":"nope","age":1.5}}`, false, []string{" required", "/age type", "/email format"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := doValidate(t, h, http.MethodPost, tt.body)
+			if rr.Code != http.StatusOK {
+				t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+			}
+
+			var resp validateResult
+			if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("invalid JSON response: %v", err)
+			}
+
+			if resp.Valid != tt.valid || resp.Errors == nil {
+				t.Errorf("expected valid=%v with an errors array, got %s", tt.valid, rr.Body.String())
+			}
+
+			if len(resp.Errors) != len(tt.errors) {
+				t.Fatalf("expected %d errors, got %v", len(tt.errors), resp.Errors)
+			}
+
+			for i, e := range resp.Errors {
+				if got := e.InstancePath + " " + e.Keyword; got != tt.errors[i] {
+					t.Errorf("error %d: got %q, want %q", i, got, tt.errors[i])
+				}
+			}
+		})
+	}
+}
+
+func TestHandler_ValidateErrors(t *testing.T) {
+	h := newTestHandler()
+
+	tests := []struct {
+		name   string
+		method string
+		body   string
+		status int
+	}{
+		{"method", http.MethodGet, ``, http.StatusMethodNotAllowed},
+		{"empty body", http.MethodPost, ``, http.StatusBadRequest},
+		{"invalid json", http.MethodPost, `{`, http.StatusBadRequest},
+		{"missing data", http.MethodPost, `{"type":"User"}`, http.StatusBadRequest},
+		{"missing type", http.MethodPost, `{"data":{}}`, http.StatusBadRequest},
+		{"unknown type", http.MethodPost, `{"type":"Nope","data":{}}`, http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := doValidate(t, h, tt.method, tt.body)
+			if rr.Code != tt.status {
+				t.Errorf("expected %d, got %d: %s", tt.status, rr.Code, rr.Body.String())
+			}
+
+			assertErrorResponse(t, rr)
+		})
+	}
+}
+
+// newTypesMux mounts the type routes as cmd/server does.
+func newTypesMux() *http.ServeMux {
+	reg := handler.NewRegistry()
+	reg.RegisterWithInfo("User", testUser{}, handler.TypeInfo{Title: "User profile", Description: "Account owner"})
+	reg.Register("Order", struct {
+		ID int `json:"id"`
+	}{})
+
+	h := handler.NewHandler(reg)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /schema/types", h.TypesHandler)
+	mux.HandleFunc("GET /schema/types/{name}", h.TypeHandler)
+	mux.HandleFunc("GET /schema/types/{name}/jsonschema", h.JSONSchemaHandler)
+	mux.HandleFunc("GET /schema/types/{name}/uischema", h.UISchemaHandler)
+
+	return mux
+}
+
+// doGet sends a GET request through mux and returns the recorder.
+func doGet(mux http.Handler, path string) *httptest.ResponseRecorder {
+	rr := httptest.NewRecorder()
+	mux.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, path, nil))
+
+	return rr
+}
+
+func TestHandler_Types(t *testing.T) {
+	rr := doGet(newTypesMux(), "/schema/types")
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+
+	var resp struct {
+		Types []handler.TypeInfo `json:"types"`
+	}
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("invalid JSON response: %v", err)
+	}
+
+	expected := []handler.TypeInfo{
+		{Name: "Order", Title: "Order"},
+		{Name: "User", Title: "User profile", Description: "Account owner"},
+	}
+	if !reflect.DeepEqual(resp.Types, expected) {
+		t.Errorf("got %+v, want %+v", resp.Types, expected)
+	}
+}
+
+func TestHandler_TypeDocuments(t *testing.T) {
+	mux := newTypesMux()
+
+	rr := doGet(mux, "/schema/types/User")
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+
+	var full struct {
+		handler.TypeInfo
+		Schema   schema.JSONSchema      `json:"schema"`
+		UISchema schema.UISchemaElement `json:"uischema"`
+	}
+	if err := json.Unmarshal(rr.Body.Bytes(), &full); err != nil {
+		t.Fatalf("invalid JSON response: %v", err)
+	}
+
+	if full.Title != "User profile" || full.Schema.Properties["name"] == nil || len(full.UISchema.Elements) != 3 {
+		t.Errorf("unexpected type document %s", rr.Body.String())
+	}
+
+	var js schema.JSONSchema
+	if rr := doGet(mux, "/schema/types/User/jsonschema"); rr.Code != http.StatusOK || json.Unmarshal(rr.Body.Bytes(), &js) != nil || js.Properties["email"].Format != "email" {
+		t.Errorf("unexpected JSON Schema response %d: %s", rr.Code, rr.Body.String())
+	}
+
+	var ui schema.UISchemaElement
+	if rr := doGet(mux, "/schema/types/User/uischema"); rr.Code != http.StatusOK || json.Unmarshal(rr.Body.Bytes(), &ui) != nil || ui.Type != "VerticalLayout" {
+		t.Errorf("unexpected UI Schema response %d: %s", rr.Code, rr.Body.String())
+	}
+}
+
+func TestHandler_TypeErrors(t *testing.T) {
+	mux := newTypesMux()
+
+	for _, path := range []string{"/schema/types/Nope", "/schema/types/Nope/jsonschema", "/schema/types/Nope/uischema"} {
+		rr := doGet(mux, path)
+		if rr.Code != http.StatusNotFound {
+			t.Errorf("%s: expected 404, got %d", path, rr.Code)
+		}
+
+		assertErrorResponse(t, rr)
+	}
+
+	h := handler.NewHandler(handler.NewRegistry())
+
+	rr := httptest.NewRecorder()
+	h.TypesHandler(rr, httptest.NewRequest(http.MethodPost, "/schema/types", nil))
+
+	if rr.Code != http.StatusMethodNotAllowed {
+		t.Errorf("expected 405, got %d", rr.Code)
+	}
+
+	rr = httptest.NewRecorder()
+	h.JSONSchemaHandler(rr, httptest.NewRequest(http.MethodGet, "/jsonschema", nil))
+
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 without a path value, got %d", rr.Code)
+	}
+}
+
+// newOptionsHandler creates a Handler with a translator and a "viewer"
+// role that cannot see the user's age.
+func newOptionsHandler() *handler.Handler {
+	reg := handler.NewRegistry()
+	reg.Register("User", testUser{})
+
+	opts := schema.DefaultOptions()
+	opts.Locale = "en"
+	opts.Translator = schema.NewMapTranslator(map[string]map[string]string{
+		"en": {"TestUser.name.label": "Name"},
+		"uk": {"TestUser.name.label": "Ім'я"},
+		"de": {"TestUser.name.label": "Vorname"},
+	})
+	opts.RolePermissions = map[string]schema.FieldPermissions{
+		"viewer": {"age": schema.AccessHidden},
+	}
+
+	return handler.NewHandlerWithOptions(reg, opts)
+}
+
+// doRequest serves a request with the given query and Accept-Language.
+func doRequest(h http.HandlerFunc, method, query, acceptLanguage, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, endpointPath+query, strings.NewReader(body))
+	req.Header.Set("Content-Type", contentTypeJSON)
+
+	if acceptLanguage != "" {
+		req.Header.Set("Accept-Language", acceptLanguage)
+	}
+
+	rr := httptest.NewRecorder()
+	h(rr, req)
+
+	return rr
+}
+
+func TestHandler_RequestLocale(t *testing.T) {
+	h := newOptionsHandler()
+
+	tests := []struct {
+		name           string
+		query          string
+		acceptLanguage string
+		body           string
+		expected       string
+	}{
+		{"handler default", "", "", `{"type":"User"}`, "Name"},
+		{"accept-language", "", "fr;q=0.9, uk-UA, de;q=0.5", `{"type":"User"}`, "Ім'я"},
+		{"unavailable accept-language", "", "fr", `{"type":"User"}`, "Name"},
+		{"query over header", "?locale=de", "uk", `{"type":"User"}`, "Vorname"},
+		{"body over query", "?locale=de", "uk", `{"type":"User","locale":"en"}`, "Name"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := doRequest(h.GenerateHandler, http.MethodPost, tt.query, tt.acceptLanguage, tt.body)
+			if rr.Code != http.StatusOK {
+				t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+			}
+
+			var resp struct {
+				UISchema schema.UISchemaElement `json:"uischema"`
+			}
+			if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("invalid JSON response: %v", err)
+			}
+
+			if label := resp.UISchema.Elements[0].Label; label != tt.expected {
+				t.Errorf("expected label %q, got %q", tt.expected, label)
+			}
+		})
+	}
+}
+
+func TestHandler_RequestRoleAndDraft(t *testing.T) {
+	h := newOptionsHandler()
+
+	// Roles chosen by the client are refused unless the handler allows them.
+	for _, req := range []struct{ query, body string }{
+		{"", `{"type":"User","role":"viewer"}`},
+		{"?role=viewer", `{"type":"User"}`},
+	} {
+		if rr := doRequest(h.GenerateHandler, http.MethodPost, req.query, "", req.body); rr.Code != http.StatusBadRequest {
+			t.Errorf("%s %s: expected 400 for a client role, got %d", req.query, req.body, rr.Code)
+		}
+	}
+
+	h.SetClientRoles(true)
+
+	rr := doRequest(h.GenerateHandler, http.MethodPost, "?draft=2019-09", "", `{"type":"User","role":"viewer"}`)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+
+	var resp struct {
+		Schema   schema.JSONSchema      `json:"schema"`
+		UISchema schema.UISchemaElement `json:"uischema"`
+	}
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("invalid JSON response: %v", err)
+	}
+
+	if resp.Schema.Schema != "https://json-schema.org/draft/2019-09/schema" {
+		t.Errorf("expected draft 2019-09, got %q", resp.Schema.Schema)
+	}
+
+	if resp.Schema.Properties["age"] != nil || len(resp.UISchema.Elements) != 2 {
+		t.Errorf("expected age hidden for viewer, got %s", rr.Body.String())
+	}
+
+	// Hidden fields are not validated for the role, but writing them is
+	// reported.
+	rr = doRequest(h.ValidateHandler, http.MethodPost, "?role=viewer", "", `{"type":"User","data":{"name":"Ann","age":"x"}}`)
+	if !strings.Contains(rr.Body.String(), `{"instancePath":"/age","keyword":"readOnly"`) || strings.Contains(rr.Body.String(), `"keyword":"type"`) {
+		t.Errorf("expected only a readOnly error for viewer, got %s", rr.Body.String())
+	}
+}
+
+func TestHandler_RequestRenderers(t *testing.T) {
+	h := newOptionsHandler()
+
+	rr := doRequest(h.GenerateHandler, http.MethodPost,
+		"?renderer=%23/properties/name=query-input&renderer=%23/properties/email=email-input", "",
+		`{"type":"User","renderers":{"#/properties/name":"body-input"}}`)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+
+	var resp struct {
+		UISchema schema.UISchemaElement `json:"uischema"`
+	}
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("invalid JSON response: %v", err)
+	}
+
+	renderers := map[string]any{}
+	for _, el := range resp.UISchema.Elements {
+		renderers[el.Scope] = el.Options["renderer"]
+	}
+
+	if renderers["#/properties/name"] != "body-input" || renderers["#/properties/email"] != "email-input" {
+		t.Errorf("expected merged renderers, got %v", renderers)
+	}
+}
+
+func TestHandler_InvalidOptions(t *testing.T) {
+	h := newOptionsHandler()
+
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+		method  string
+		query   string
+		body    string
+	}{
+		{"body draft", h.GenerateHandler, http.MethodPost, "", `{"type":"User","draft":"draft-04"}`},
+		{"query draft", h.ValidateHandler, http.MethodPost, "?draft=2020-12", `{"type":"User","data":{}}`},
+		{"renderer", h.GenerateHandler, http.MethodPost, "?renderer=name", `{"type":"User"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := doRequest(tt.handler, tt.method, tt.query, "", tt.body)
+			if rr.Code != http.StatusBadRequest {
+				t.Errorf("expected 400, got %d: %s", rr.Code, rr.Body.String())
+			}
+
+			assertErrorResponse(t, rr)
+		})
+	}
+}
+
+func TestHandler_TypeQueryOptions(t *testing.T) {
+	reg := handler.NewRegistry()
+	reg.Register("User", testUser{})
+
+	opts := schema.DefaultOptions()
+	opts.RolePermissions = map[string]schema.FieldPermissions{"viewer": {"email": schema.AccessHidden}}
+
+	h := handler.NewHandlerWithOptions(reg, opts)
+	h.SetClientRoles(true)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /schema/types/{name}/jsonschema", h.JSONSchemaHandler)
+
+	var js schema.JSONSchema
+	if rr := doGet(mux, "/schema/types/User/jsonschema?role=viewer"); rr.Code != http.StatusOK || json.Unmarshal(rr.Body.Bytes(), &js) != nil || js.Properties["email"] != nil {
+		t.Errorf("expected email hidden for viewer, got %s", rr.Body.String())
+	}
+
+	if rr := doGet(mux, "/schema/types/User/jsonschema?draft=nope"); rr.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 for an invalid draft, got %d", rr.Code)
+	}
+}
+
+func TestHandler_ValidateForbiddenWrites(t *testing.T) {
+	h := newOptionsHandler()
+	body := `{"type":"User","data":{"name":"Ann","email":"ann@example.com","Age":30}}`
+
+	req := httptest.NewRequest(http.MethodPost, "/schema/validate", strings.NewReader(body))
+	req.Header.Set("Content-Type", contentTypeJSON)
+	req = req.WithContext(handler.ContextWithRoles(req.Context(), []string{"viewer"}))
+
+	rr := httptest.NewRecorder()
+	h.ValidateHandler(rr, req)
+
+	var resp validateResult
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("invalid JSON response: %v", err)
+	}
+
+	if resp.Valid || len(resp.Errors) != 1 || resp.Errors[0].InstancePath != "/Age" || resp.Errors[0].Keyword != "readOnly" {
+		t.Errorf("expected the hidden age to be reported whatever its case, got %s", rr.Body.String())
+	}
+
+	// Without a role every field without "*" tag entries may be written.
+	if rr = doValidate(t, h, http.MethodPost, body); !strings.Contains(rr.Body.String(), `"valid":true`) {
+		t.Errorf("expected valid data without a role, got %s", rr.Body.String())
+	}
+}
+
+type taggedInvoice struct {
+	Total  float64 `json:"total"`
+	Status string  `json:"status" access:"admin=rw;*=ro"`
+}
+
+func TestHandler_ValidateForbiddenWritesWithoutRole(t *testing.T) {
+	reg := handler.NewRegistry()
+	reg.Register("Invoice", taggedInvoice{})
+
+	rr := doValidate(t, handler.NewHandler(reg), http.MethodPost, `{"type":"Invoice","data":{"total":10,"status":"paid"}}`)
+
+	var resp validateResult
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("invalid JSON response: %v", err)
+	}
+
+	if resp.Valid || len(resp.Errors) != 1 || resp.Errors[0].InstancePath != "/status" || resp.Errors[0].Keyword != "readOnly" {
+		t.Errorf("expected the *=ro status to be reported without a role, got %s", rr.Body.String())
+	}
+}
@@ -0,0 +1,316 @@
+package handler
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/hmac"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+	"time"
+)
+
+// ErrInvalidKey is returned when a JWT verification key cannot be loaded.
+var ErrInvalidKey = errors.New("invalid JWT key")
+
+// defaultRoleClaim is the claim read by JWTResolver when RoleClaim is empty.
+const defaultRoleClaim = "role"
+
+// hashes maps the supported JWT algorithms to their hash functions.
+var hashes = map[string]crypto.Hash{
+	"HS256": crypto.SHA256,
+	"HS384": crypto.SHA384,
+	"HS512": crypto.SHA512,
+	"RS256": crypto.SHA256,
+	"RS384": crypto.SHA384,
+	"RS512": crypto.SHA512,
+}
+
+// JWTResolver resolves roles from JWT bearer tokens signed with HMAC
+// (HS256, HS384, HS512) or RSA (RS256, RS384, RS512). The key type fixes
+// the accepted algorithms, so an HMAC token is never checked against an
+// RSA public key. The exp and nbf claims are enforced when present.
+type JWTResolver struct {
+	// RoleClaim names the claim holding the role, a string or an array of
+	// strings. Default is "role".
+	RoleClaim string
+	// Issuer, if set, must equal the iss claim.
+	Issuer string
+	// Audience, if set, must be listed in the aud claim.
+	Audience string
+	// Leeway tolerates clock skew when checking exp and nbf.
+	Leeway time.Duration
+
+	secret []byte
+	key    *rsa.PublicKey
+}
+
+// NewHMACJWTResolver creates a resolver verifying HS256/384/512 tokens
+// with secret.
+func NewHMACJWTResolver(secret []byte) *JWTResolver {
+	return &JWTResolver{secret: secret}
+}
+
+// NewRSAJWTResolver creates a resolver verifying RS256/384/512 tokens
+// with key.
+func NewRSAJWTResolver(key *rsa.PublicKey) *JWTResolver {
+	return &JWTResolver{key: key}
+}
+
+// LoadJWTResolver creates a resolver from a local key file. A PEM file
+// holding an RSA public key ("PUBLIC KEY" or "RSA PUBLIC KEY") or a
+// certificate selects RSA verification; any other content, trimmed of
+// surrounding whitespace, is the HMAC secret.
+func LoadJWTResolver(path string) (*JWTResolver, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // path comes from the operator
+	if err != nil {
+		return nil, err
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		secret := bytes.TrimSpace(data)
+		if len(secret) == 0 {
+			return nil, fmt.Errorf("%w: %s is empty", ErrInvalidKey, path)
+		}
+
+		return NewHMACJWTResolver(secret), nil
+	}
+
+	key, err := parseRSAPublicKey(block)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: %v", ErrInvalidKey, path, err) //nolint:errorlint // wrapping intentional
+	}
+
+	return NewRSAJWTResolver(key), nil
+}
+
+// parseRSAPublicKey parses the RSA public key of a PEM block.
+func parseRSAPublicKey(block *pem.Block) (*rsa.PublicKey, error) {
+	var (
+		key any
+		err error
+	)
+
+	switch block.Type {
+	case "PUBLIC KEY":
+		key, err = x509.ParsePKIXPublicKey(block.Bytes)
+	case "RSA PUBLIC KEY":
+		key, err = x509.ParsePKCS1PublicKey(block.Bytes)
+	case "CERTIFICATE":
+		var cert *x509.Certificate
+
+		cert, err = x509.ParseCertificate(block.Bytes)
+		if err == nil {
+			key = cert.PublicKey
+		}
+	default:
+		return nil, fmt.Errorf("unsupported PEM block %q", block.Type)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	rsaKey, ok := key.(*rsa.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("%T is not an RSA public key", key)
+	}
+
+	return rsaKey, nil
+}
+
+// ResolveRoles implements RoleResolver.
+func (j *JWTResolver) ResolveRoles(r *http.Request) ([]string, error) {
+	token, err := bearerToken(r)
+	if err != nil {
+		return nil, err
+	}
+
+	claims, err := j.verify(token)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCredentials, err) //nolint:errorlint // wrapping intentional
+	}
+
+	claim := j.RoleClaim
+	if claim == "" {
+		claim = defaultRoleClaim
+	}
+
+	return stringList(claims[claim]), nil
+}
+
+// jwtClaims holds the registered claims checked by JWTResolver.
+type jwtClaims struct {
+	Expires   *float64 `json:"exp"`
+	NotBefore *float64 `json:"nbf"`
+	Issuer    string   `json:"iss"`
+	Audience  any      `json:"aud"`
+}
+
+// verify checks the signature and registered claims of token and returns
+// all its claims.
+func (j *JWTResolver) verify(token string) (map[string]any, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, errors.New("malformed token")
+	}
+
+	var header struct {
+		Alg string `json:"alg"`
+	}
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return nil, fmt.Errorf("header: %w", err)
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("signature: %w", err)
+	}
+
+	if err := j.verifySignature(header.Alg, parts[0]+"."+parts[1], signature); err != nil {
+		return nil, err
+	}
+
+	var registered jwtClaims
+	if err := decodeSegment(parts[1], &registered); err != nil {
+		return nil, fmt.Errorf("claims: %w", err)
+	}
+
+	if err := j.checkClaims(registered); err != nil {
+		return nil, err
+	}
+
+	var claims map[string]any
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return nil, fmt.Errorf("claims: %w", err)
+	}
+
+	return claims, nil
+}
+
+// verifySignature checks signature of signed with algorithm alg.
+func (j *JWTResolver) verifySignature(alg, signed string, signature []byte) error {
+	hash, ok := hashes[alg]
+	if !ok {
+		return fmt.Errorf("unsupported algorithm %q", alg)
+	}
+
+	switch {
+	case j.secret != nil && strings.HasPrefix(alg, "HS"):
+		mac := hmac.New(hash.New, j.secret)
+		mac.Write([]byte(signed))
+
+		if !hmac.Equal(mac.Sum(nil), signature) {
+			return errors.New("signature mismatch")
+		}
+	case j.key != nil && strings.HasPrefix(alg, "RS"):
+		h := hash.New()
+		h.Write([]byte(signed))
+
+		if err := rsa.VerifyPKCS1v15(j.key, hash, h.Sum(nil), signature); err != nil {
+			return errors.New("signature mismatch")
+		}
+	default:
+		return fmt.Errorf("algorithm %q does not match the key", alg)
+	}
+
+	return nil
+}
+
+// checkClaims enforces exp, nbf, iss and aud.
+func (j *JWTResolver) checkClaims(c jwtClaims) error {
+	now := time.Now()
+
+	if c.Expires != nil {
+		exp, err := unixTime("exp", *c.Expires)
+		if err != nil {
+			return err
+		}
+
+		if now.After(exp.Add(j.Leeway)) {
+			return errors.New("token expired")
+		}
+	}
+
+	if c.NotBefore != nil {
+		nbf, err := unixTime("nbf", *c.NotBefore)
+		if err != nil {
+			return err
+		}
+
+		if now.Before(nbf.Add(-j.Leeway)) {
+			return errors.New("token not valid yet")
+		}
+	}
+
+	if j.Issuer != "" && c.Issuer != j.Issuer {
+		return fmt.Errorf("unexpected issuer %q", c.Issuer)
+	}
+
+	if j.Audience != "" && !slices.Contains(stringList(c.Audience), j.Audience) {
+		return fmt.Errorf("token not issued for audience %q", j.Audience)
+	}
+
+	return nil
+}
+
+// decodeSegment decodes a base64url JSON segment of a token into v.
+func decodeSegment(segment string, v any) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(data, v)
+}
+
+// maxNumericDate bounds the NumericDate claims accepted, in seconds from
+// the epoch either way, well within the range of time.Time.
+const maxNumericDate = 1 << 62
+
+// unixTime converts the NumericDate claim name to a time. Non-finite and
+// out-of-range values are malformed.
+func unixTime(name string, seconds float64) (time.Time, error) {
+	if math.IsNaN(seconds) || math.Abs(seconds) >= maxNumericDate {
+		return time.Time{}, fmt.Errorf("malformed %s claim", name)
+	}
+
+	sec, frac := math.Modf(seconds)
+
+	return time.Unix(int64(sec), int64(frac*float64(time.Second))), nil
+}
+
+// stringList returns a string claim as a one-element list and the strings
+// of an array claim; anything else yields nil.
+func stringList(v any) []string {
+	switch val := v.(type) {
+	case string:
+		if val == "" {
+			return nil
+		}
+
+		return []string{val}
+	case []any:
+		var list []string
+
+		for _, item := range val {
+			if s, ok := item.(string); ok && s != "" {
+				list = append(list, s)
+			}
+		}
+
+		return list
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,185 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/holdemlab/ui-json-schema/schema"
+)
+
+// ErrInvalidOption is returned for a malformed per-request option.
+var ErrInvalidOption = errors.New("invalid option")
+
+// SetClientRoles lets requests choose their role with the "role" body
+// field or query parameter. It is off by default, as a role a client
+// chooses is not authenticated: enable it only when every client is
+// trusted, e.g. to preview forms per role. Roles resolved by
+// RoleMiddleware always take precedence.
+func (h *Handler) SetClientRoles(allowed bool) {
+	h.clientRoles = allowed
+}
+
+// Query parameters selecting per-request options.
+const (
+	queryLocale   = "locale"
+	queryRole     = "role"
+	queryDraft    = "draft"
+	queryRenderer = "renderer"
+)
+
+// supportedDrafts lists the accepted values of the draft option.
+var supportedDrafts = []string{"draft-07", "2019-09"}
+
+// requestOptions holds the per-request option overrides of a POST body.
+type requestOptions struct {
+	// Locale selects the translation locale.
+	Locale string `json:"locale,omitempty"`
+	// Role selects the role whose permissions apply.
+	Role string `json:"role,omitempty"`
+	// Draft selects the JSON Schema draft ("draft-07" or "2019-09").
+	Draft string `json:"draft,omitempty"`
+	// Renderers maps scopes to custom renderer names.
+	Renderers map[string]string `json:"renderers,omitempty"`
+}
+
+// options returns the handler options overridden for request r. Each
+// option is taken from the body (o), else the query parameters, else the
+// handler options; the locale may also come from Accept-Language.
+// Renderers from all three sources are merged, the body winning. Roles
+// resolved by RoleMiddleware replace the role options altogether; without
+// them a requested role is an ErrInvalidOption unless SetClientRoles
+// allows it.
+func (h *Handler) options(r *http.Request, o requestOptions) (schema.Options, error) {
+	opts := h.opts
+	query := r.URL.Query()
+
+	if locale := firstNonEmpty(o.Locale, query.Get(queryLocale), negotiateLocale(r.Header.Get("Accept-Language"), opts.Translator)); locale != "" {
+		opts.Locale = locale
+	}
+
+	if roles, ok := RolesFromContext(r.Context()); ok {
+		opts.Role, opts.Roles = "", roles
+	} else if role := firstNonEmpty(o.Role, query.Get(queryRole)); role != "" {
+		if !h.clientRoles {
+			return opts, fmt.Errorf("%w: role %q, roles come from authentication", ErrInvalidOption, role)
+		}
+
+		opts.Role = role
+	}
+
+	if draft := firstNonEmpty(o.Draft, query.Get(queryDraft)); draft != "" {
+		if !slices.Contains(supportedDrafts, draft) {
+			return opts, fmt.Errorf("%w: draft %q, use one of %s", ErrInvalidOption, draft, strings.Join(supportedDrafts, ", "))
+		}
+
+		opts.Draft = draft
+	}
+
+	renderers := maps.Clone(opts.Renderers)
+	if renderers == nil {
+		renderers = make(map[string]string)
+	}
+
+	for _, entry := range query[queryRenderer] {
+		scope, name, ok := strings.Cut(entry, "=")
+		if !ok || scope == "" || name == "" {
+			return opts, fmt.Errorf("%w: renderer %q, use scope=name", ErrInvalidOption, entry)
+		}
+
+		renderers[scope] = name
+	}
+
+	maps.Copy(renderers, o.Renderers)
+
+	if len(renderers) > 0 {
+		opts.Renderers = renderers
+	}
+
+	return opts, nil
+}
+
+// negotiateLocale picks the locale for an Accept-Language header. When tr
+// lists its locales, the preferred listed locale wins, trying each range
+// and its parents ("uk-UA" → "uk"); otherwise the preferred range is used.
+// It returns "" when nothing matches.
+func negotiateLocale(header string, tr schema.Translator) string {
+	ranges := parseAcceptLanguage(header)
+
+	lister, ok := tr.(schema.LocaleLister)
+	if !ok {
+		if len(ranges) > 0 {
+			return ranges[0]
+		}
+
+		return ""
+	}
+
+	available := lister.Locales()
+
+	for _, lang := range ranges {
+		for _, candidate := range schema.FallbackChain(lang, "") {
+			for _, locale := range available {
+				if strings.EqualFold(locale, candidate) {
+					return locale
+				}
+			}
+		}
+	}
+
+	return ""
+}
+
+// parseAcceptLanguage returns the language ranges of an Accept-Language
+// header by descending quality, skipping "*" and ranges with q=0.
+func parseAcceptLanguage(header string) []string {
+	type weighted struct {
+		lang string
+		q    float64
+	}
+
+	var ranges []weighted
+
+	for _, part := range strings.Split(header, ",") {
+		lang, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		q := 1.0
+
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				continue
+			}
+
+			q = parsed
+		}
+
+		if lang = strings.TrimSpace(lang); lang != "" && lang != "*" && q > 0 {
+			ranges = append(ranges, weighted{lang, q})
+		}
+	}
+
+	sort.SliceStable(ranges, func(i, j int) bool { return ranges[i].q > ranges[j].q })
+
+	langs := make([]string, len(ranges))
+	for i, r := range ranges {
+		langs[i] = r.lang
+	}
+
+	return langs
+}
+
+// firstNonEmpty returns the first non-empty value, or "".
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+
+	return ""
+}
@@ -2,21 +2,48 @@
 package handler
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
+	"reflect"
+	"sort"
 	"sync"
+
+	"github.com/holdemlab/ui-json-schema/parser"
+	"github.com/holdemlab/ui-json-schema/schema"
 )
 
+// ErrTypeNotFound is returned when no type is registered under a name.
+var ErrTypeNotFound = errors.New("type not found in registry")
+
 // Registry holds a mapping of type names to Go struct instances
 // that can be used for schema generation by name.
 type Registry struct {
-	mu    sync.RWMutex
-	types map[string]any
+	mu        sync.RWMutex
+	types     map[string]any
+	templates map[string]*schema.UISchemaTemplate
+	infos     map[string]TypeInfo
+	// version counts the changes, so cached responses of older
+	// registrations are never served.
+	version uint64
+}
+
+// TypeInfo describes a registered type in the type listing.
+type TypeInfo struct {
+	// Name is the registered type name.
+	Name string `json:"name"`
+	// Title is a human-readable title; defaults to Name.
+	Title string `json:"title"`
+	// Description optionally describes the type.
+	Description string `json:"description,omitempty"`
 }
 
 // NewRegistry creates an empty type registry.
 func NewRegistry() *Registry {
 	return &Registry{
-		types: make(map[string]any),
+		types:     make(map[string]any),
+		templates: make(map[string]*schema.UISchemaTemplate),
+		infos:     make(map[string]TypeInfo),
 	}
 }
 
@@ -27,17 +54,114 @@ func (r *Registry) Register(name string, v any) {
 	defer r.mu.Unlock()
 
 	r.types[name] = v
+	r.version++
+}
+
+// RegisterWithInfo adds a Go struct instance to the registry like Register
+// and sets the title and description listed for it. info.Name is ignored.
+func (r *Registry) RegisterWithInfo(name string, v any, info TypeInfo) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.types[name] = v
+	info.Name = name
+	r.infos[name] = info
+	r.version++
+}
+
+// Info returns the listing entry of the type registered under name, with
+// the title defaulting to the name. Returns an error if the name is not
+// found.
+func (r *Registry) Info(name string) (TypeInfo, error) {
+	if _, err := r.Lookup(name); err != nil {
+		return TypeInfo{}, err
+	}
+
+	r.mu.RLock()
+	info := r.infos[name]
+	r.mu.RUnlock()
+
+	info.Name = name
+	if info.Title == "" {
+		info.Title = name
+	}
+
+	return info, nil
+}
+
+// Infos returns the listing entries of all registered types, sorted by name.
+func (r *Registry) Infos() []TypeInfo {
+	names := r.Names()
+	infos := make([]TypeInfo, 0, len(names))
+
+	for _, name := range names {
+		if info, err := r.Info(name); err == nil {
+			infos = append(infos, info)
+		}
+	}
+
+	return infos
+}
+
+// RegisterType adds a Go struct instance to the registry under its type
+// name (see parser.TypeName), so generic instantiations such as Page[User]
+// are registered as "PageOfUser". It returns the name used.
+func (r *Registry) RegisterType(v any) string {
+	return r.RegisterTypeWithOptions(v, schema.DefaultOptions())
+}
+
+// RegisterTypeWithOptions is RegisterType naming the type with
+// opts.TypeNamer, falling back to parser.TypeName.
+func (r *Registry) RegisterTypeWithOptions(v any, opts schema.Options) string {
+	name := parser.TypeNameWithOptions(reflect.TypeOf(v), opts)
+	r.Register(name, v)
+
+	return name
+}
+
+// RegisterTemplate attaches a UI Schema layout template to the type
+// registered under the given name. The template replaces the struct field
+// order when the type's UI Schema is generated. A nil template removes it.
+func (r *Registry) RegisterTemplate(name string, tmpl *schema.UISchemaTemplate) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.version++
+
+	if tmpl == nil {
+		delete(r.templates, name)
+		return
+	}
+
+	r.templates[name] = tmpl
+}
+
+// revision returns the number of changes made to the registry.
+func (r *Registry) revision() uint64 {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.version
+}
+
+// Template returns the UI Schema template registered for the given name,
+// or nil if there is none.
+func (r *Registry) Template(name string) *schema.UISchemaTemplate {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.templates[name]
 }
 
 // Lookup returns the struct instance registered under the given name.
-// Returns an error if the name is not found.
+// Returns an error wrapping ErrTypeNotFound if the name is not found.
 func (r *Registry) Lookup(name string) (any, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
 	v, ok := r.types[name]
 	if !ok {
-		return nil, fmt.Errorf("type %q not found in registry", name)
+		return nil, fmt.Errorf("%w: %q", ErrTypeNotFound, name)
 	}
 
 	return v, nil
@@ -53,5 +177,105 @@ func (r *Registry) Names() []string {
 		names = append(names, name)
 	}
 
+	sort.Strings(names)
+
 	return names
 }
+
+// FilterWrite enforces the permissions of role on data submitted for the
+// type registered under name (see parser.FilterWrite). opts supplies
+// RolePermissions and related settings; its Role is replaced by role.
+// It returns the permitted data and the JSON Pointers of dropped values.
+func (r *Registry) FilterWrite(name, role string, data []byte, opts schema.Options) (map[string]any, []string, error) {
+	v, err := r.Lookup(name)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var value any
+	if err := json.Unmarshal(data, &value); err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", parser.ErrInvalidJSON, err) //nolint:errorlint // wrapping intentional
+	}
+
+	obj, ok := value.(map[string]any)
+	if !ok {
+		return nil, nil, parser.ErrNotJSONObject
+	}
+
+	opts.Role = role
+
+	return parser.FilterWrite(v, obj, opts)
+}
+
+// Messages returns the translatable messages of all registered types
+// (with their templates) generated with opts, merged and sorted by key.
+// Save parser.Catalogue(messages) as JSON to get a translation template.
+func (r *Registry) Messages(opts schema.Options) ([]parser.Message, error) {
+	merged := make(map[string]parser.Message)
+
+	for _, name := range r.Names() {
+		messages, err := r.typeMessages(name, opts)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, m := range messages {
+			if _, ok := merged[m.Key]; !ok {
+				merged[m.Key] = m
+			}
+		}
+	}
+
+	result := make([]parser.Message, 0, len(merged))
+	for _, m := range merged {
+		result = append(result, m)
+	}
+
+	sort.Slice(result, func(i, j int) bool { return result[i].Key < result[j].Key })
+
+	return result, nil
+}
+
+// MissingTranslations reports, per registered type name and locale, the
+// keys opts.Translator cannot translate (see parser.MissingTranslations).
+// Complete types are omitted.
+func (r *Registry) MissingTranslations(opts schema.Options, locales ...string) (map[string]map[string][]string, error) {
+	report := make(map[string]map[string][]string)
+
+	for _, name := range r.Names() {
+		v, err := r.Lookup(name)
+		if err != nil {
+			return nil, err
+		}
+
+		opts.Template = r.Template(name)
+
+		missing, err := parser.MissingTranslations(v, opts, locales...)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+
+		if len(missing) > 0 {
+			report[name] = missing
+		}
+	}
+
+	return report, nil
+}
+
+// typeMessages extracts the messages of the type registered under name.
+func (r *Registry) typeMessages(name string, opts schema.Options) ([]parser.Message, error) {
+	v, err := r.Lookup(name)
+	if err != nil {
+		return nil, err
+	}
+
+	opts.Template = r.Template(name)
+
+	messages, err := parser.ExtractMessages(v, opts)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+
+	return messages, nil
+}
@@ -1,9 +1,13 @@
 package handler_test
 
 import (
+	"errors"
+	"reflect"
 	"testing"
 
 	handler "github.com/holdemlab/ui-json-schema/api"
+	"github.com/holdemlab/ui-json-schema/parser"
+	"github.com/holdemlab/ui-json-schema/schema"
 )
 
 type testUser struct {
@@ -30,8 +34,8 @@ func TestRegistry_LookupNotFound(t *testing.T) {
 	r := handler.NewRegistry()
 
 	_, err := r.Lookup("NonExistent")
-	if err == nil {
-		t.Fatal("expected error for missing type")
+	if !errors.Is(err, handler.ErrTypeNotFound) {
+		t.Fatalf("expected ErrTypeNotFound, got %v", err)
 	}
 }
 
@@ -64,13 +68,35 @@ func TestRegistry_Names(t *testing.T) {
 		t.Fatalf("expected 2 names, got %d", len(names))
 	}
 
-	nameSet := make(map[string]bool)
-	for _, n := range names {
-		nameSet[n] = true
+	if names[0] != "Alpha" || names[1] != "Beta" {
+		t.Errorf("expected sorted names [Alpha Beta], got %v", names)
+	}
+}
+
+func TestRegistry_Info(t *testing.T) {
+	r := handler.NewRegistry()
+	r.Register("User", testUser{})
+	r.RegisterWithInfo("Order", struct{}{}, handler.TypeInfo{Name: "ignored", Title: "Order form", Description: "Checkout"})
+
+	info, err := r.Info("Order")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if info != (handler.TypeInfo{Name: "Order", Title: "Order form", Description: "Checkout"}) {
+		t.Errorf("unexpected info %+v", info)
 	}
 
-	if !nameSet["Alpha"] || !nameSet["Beta"] {
-		t.Errorf("expected Alpha and Beta in names, got %v", names)
+	expected := []handler.TypeInfo{
+		{Name: "Order", Title: "Order form", Description: "Checkout"},
+		{Name: "User", Title: "User"},
+	}
+	if infos := r.Infos(); !reflect.DeepEqual(infos, expected) {
+		t.Errorf("got %+v, want %+v", infos, expected)
+	}
+
+	if _, err := r.Info("Nope"); err == nil {
+		t.Error("expected error for an unknown type")
 	}
 }
 
@@ -82,3 +108,151 @@ func TestRegistry_NamesEmpty(t *testing.T) {
 		t.Errorf("expected 0 names, got %d", len(names))
 	}
 }
+
+func TestRegistry_Template(t *testing.T) {
+	r := handler.NewRegistry()
+	r.Register("User", testUser{})
+
+	if r.Template("User") != nil {
+		t.Fatal("expected no template by default")
+	}
+
+	tmpl := &schema.UISchemaTemplate{Elements: []*schema.UISchemaTemplate{{Field: "email"}}}
+	r.RegisterTemplate("User", tmpl)
+
+	if r.Template("User") != tmpl {
+		t.Error("expected registered template")
+	}
+
+	r.RegisterTemplate("User", nil)
+
+	if r.Template("User") != nil {
+		t.Error("expected template to be removed")
+	}
+}
+
+type testPage[T any] struct {
+	Items []T `json:"items"`
+	Total int `json:"total"`
+}
+
+func TestRegistry_RegisterType(t *testing.T) {
+	r := handler.NewRegistry()
+
+	if name := r.RegisterType(testUser{}); name != "TestUser" {
+		t.Errorf("expected TestUser, got %q", name)
+	}
+
+	name := r.RegisterType(&testPage[testUser]{})
+	if name != "TestPageOfTestUser" {
+		t.Errorf("expected TestPageOfTestUser, got %q", name)
+	}
+
+	if _, err := r.Lookup(name); err != nil {
+		t.Errorf("expected %q to be registered: %v", name, err)
+	}
+}
+
+type testInvoice struct {
+	Number string `json:"number"`
+	Name   string `json:"name" i18n:"user.name"`
+}
+
+type testAccount struct {
+	Name string `json:"name" i18n:"user.name"`
+}
+
+func TestRegistry_Messages(t *testing.T) {
+	r := handler.NewRegistry()
+	r.Register("Invoice", testInvoice{})
+	r.Register("Account", testAccount{})
+
+	messages, err := r.Messages(schema.DefaultOptions())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(messages) != 2 || messages[0].Key != "TestInvoice.number.label" || messages[1].Key != "user.name" {
+		t.Errorf("expected merged sorted messages, got %+v", messages)
+	}
+}
+
+func TestRegistry_MissingTranslations(t *testing.T) {
+	r := handler.NewRegistry()
+	r.Register("Invoice", testInvoice{})
+	r.Register("Account", testAccount{})
+
+	opts := schema.DefaultOptions()
+	opts.Translator = schema.NewMapTranslator(map[string]map[string]string{
+		"uk": {"user.name": "Ім'я"},
+	})
+
+	report, err := r.MissingTranslations(opts)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, ok := report["Account"]; ok {
+		t.Errorf("expected Account to be complete, got %v", report["Account"])
+	}
+
+	if got := report["Invoice"]["uk"]; len(got) != 1 || got[0] != "TestInvoice.number.label" {
+		t.Errorf("expected missing invoice number label, got %v", report["Invoice"])
+	}
+}
+
+func TestRegistry_FilterWrite(t *testing.T) {
+	reg := handler.NewRegistry()
+	reg.Register("User", testUser{})
+
+	opts := schema.DefaultOptions()
+	opts.RolePermissions = map[string]schema.FieldPermissions{
+		"viewer": {"email": schema.AccessReadOnly, "age": schema.AccessHidden},
+	}
+
+	data, removed, err := reg.FilterWrite("User", "viewer", []byte(`{"name":"Ann","email":"a@b.c","age":3}`), opts)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !reflect.DeepEqual(data, map[string]any{"name": "Ann"}) || !reflect.DeepEqual(removed, []string{"/age", "/email"}) {
+		t.Errorf("unexpected result %v, removed %v", data, removed)
+	}
+
+	if _, _, err := reg.FilterWrite("User", "viewer", []byte(`[1]`), opts); !errors.Is(err, parser.ErrNotJSONObject) {
+		t.Errorf("expected ErrNotJSONObject, got %v", err)
+	}
+
+	if _, _, err := reg.FilterWrite("User", "viewer", []byte(`{`), opts); !errors.Is(err, parser.ErrInvalidJSON) {
+		t.Errorf("expected ErrInvalidJSON, got %v", err)
+	}
+
+	if _, _, err := reg.FilterWrite("Nope", "viewer", []byte(`{}`), opts); err == nil {
+		t.Error("expected error for an unknown type")
+	}
+}
+
+func TestRegistry_RegisterTypeWithOptions(t *testing.T) {
+	r := handler.NewRegistry()
+
+	opts := schema.DefaultOptions()
+	opts.TypeNamer = func(t reflect.Type) string {
+		if t == reflect.TypeOf(testUser{}) {
+			return "Account"
+		}
+
+		return ""
+	}
+
+	if name := r.RegisterTypeWithOptions(&testUser{}, opts); name != "Account" {
+		t.Errorf("expected the namer's Account, got %q", name)
+	}
+
+	if name := r.RegisterTypeWithOptions(testPage[testUser]{}, opts); name != "TestPageOfTestUser" {
+		t.Errorf("expected the TypeName fallback, got %q", name)
+	}
+
+	if _, err := r.Lookup("Account"); err != nil {
+		t.Errorf("expected Account to be registered: %v", err)
+	}
+}
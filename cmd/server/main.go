@@ -2,12 +2,18 @@
 package main
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
+	"net/netip"
 	"os"
+	"strconv"
+	"strings"
 
 	handler "github.com/holdemlab/ui-json-schema/api"
+	"github.com/holdemlab/ui-json-schema/schema"
 )
 
 const defaultAddr = ":8080"
@@ -18,13 +24,169 @@ func main() {
 		addr = v
 	}
 
+	opts, err := loadOptions()
+	if err != nil {
+		log.Fatal(err)
+	}
+
 	registry := handler.NewRegistry()
 
-	h := handler.NewHandler(registry)
+	h := handler.NewHandlerWithOptions(registry, opts)
+
+	cacheOpts, err := loadCacheOptions()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	h.SetCacheOptions(cacheOpts)
 
 	mux := http.NewServeMux()
 	mux.HandleFunc("/schema/generate", h.GenerateHandler)
+	mux.HandleFunc("/schema/validate", h.ValidateHandler)
+	mux.HandleFunc("GET /schema/types", h.TypesHandler)
+	mux.HandleFunc("GET /schema/types/{name}", h.TypeHandler)
+	mux.HandleFunc("GET /schema/types/{name}/jsonschema", h.JSONSchemaHandler)
+	mux.HandleFunc("GET /schema/types/{name}/uischema", h.UISchemaHandler)
+	mux.HandleFunc("GET /schema/cache", h.CacheStatsHandler)
+
+	resolver, err := loadResolver()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Roles chosen by clients are not accepted, so permissions without
+	// authenticated roles would never apply.
+	if resolver == nil && opts.RolePermissions != nil {
+		log.Fatal("PERMISSIONS_FILE requires one of JWT_KEY_FILE, ROLE_HEADER and TOKENS_FILE")
+	}
+
+	var srv http.Handler = mux
+	if resolver != nil {
+		srv = handler.RoleMiddleware(resolver, mux)
+	}
 
 	fmt.Printf("ui-json-schema server listening on %s\n", addr)
-	log.Fatal(http.ListenAndServe(addr, mux)) //nolint:gosec // demo server, no TLS needed
+	log.Fatal(http.ListenAndServe(addr, srv)) //nolint:gosec // demo server, no TLS needed
+}
+
+// loadOptions builds the handler options from the environment:
+// I18N_DIR is a directory of translation files, DEFAULT_LOCALE the
+// fallback locale and PERMISSIONS_FILE a JSON file mapping roles to
+// field permissions, e.g. {"viewer": {"salary": "hidden"}}.
+func loadOptions() (schema.Options, error) {
+	opts := schema.DefaultOptions()
+	opts.Locale = os.Getenv("DEFAULT_LOCALE")
+
+	if dir := os.Getenv("I18N_DIR"); dir != "" {
+		tr, err := schema.LoadTranslatorDir(dir)
+		if err != nil {
+			return opts, fmt.Errorf("load translations: %w", err)
+		}
+
+		opts.Translator = schema.NewFallbackTranslator(tr, opts.Locale)
+	}
+
+	if file := os.Getenv("PERMISSIONS_FILE"); file != "" {
+		data, err := os.ReadFile(file) //nolint:gosec // path comes from the operator
+		if err != nil {
+			return opts, fmt.Errorf("read permissions: %w", err)
+		}
+
+		if err := json.Unmarshal(data, &opts.RolePermissions); err != nil {
+			return opts, fmt.Errorf("parse permissions: %w", err)
+		}
+	}
+
+	return opts, nil
+}
+
+// loadCacheOptions builds the cache options from the environment:
+// CACHE_SIZE is the number of cached responses (0 disables the cache) and
+// CACHE_CONTROL the Cache-Control header of type responses.
+func loadCacheOptions() (handler.CacheOptions, error) {
+	o := handler.DefaultCacheOptions()
+
+	if v := os.Getenv("CACHE_SIZE"); v != "" {
+		size, err := strconv.Atoi(v)
+		if err != nil {
+			return o, fmt.Errorf("parse CACHE_SIZE: %w", err)
+		}
+
+		o.Size = size
+	}
+
+	if v, ok := os.LookupEnv("CACHE_CONTROL"); ok {
+		o.CacheControl = v
+	}
+
+	return o, nil
+}
+
+// loadResolver builds the role resolver from the environment, or returns
+// nil when roles are not authenticated. At most one of JWT_KEY_FILE (with
+// JWT_ROLE_CLAIM, JWT_ISSUER and JWT_AUDIENCE), ROLE_HEADER (with
+// comma-separated TRUSTED_PROXIES prefixes, at least one) and TOKENS_FILE
+// may be set.
+// ANONYMOUS_ROLE grants a role to requests without credentials.
+func loadResolver() (handler.RoleResolver, error) {
+	var resolvers []handler.RoleResolver
+
+	if file := os.Getenv("JWT_KEY_FILE"); file != "" {
+		jwt, err := handler.LoadJWTResolver(file)
+		if err != nil {
+			return nil, err
+		}
+
+		jwt.RoleClaim = os.Getenv("JWT_ROLE_CLAIM")
+		jwt.Issuer = os.Getenv("JWT_ISSUER")
+		jwt.Audience = os.Getenv("JWT_AUDIENCE")
+		resolvers = append(resolvers, jwt)
+	}
+
+	if header := os.Getenv("ROLE_HEADER"); header != "" {
+		var proxies []netip.Prefix
+
+		for _, p := range strings.Split(os.Getenv("TRUSTED_PROXIES"), ",") {
+			if p = strings.TrimSpace(p); p == "" {
+				continue
+			}
+
+			prefix, err := netip.ParsePrefix(p)
+			if err != nil {
+				return nil, fmt.Errorf("parse TRUSTED_PROXIES: %w", err)
+			}
+
+			proxies = append(proxies, prefix)
+		}
+
+		res, err := handler.NewHeaderResolver(header, proxies...)
+		if err != nil {
+			return nil, fmt.Errorf("ROLE_HEADER requires TRUSTED_PROXIES: %w", err)
+		}
+
+		resolvers = append(resolvers, res)
+	}
+
+	if file := os.Getenv("TOKENS_FILE"); file != "" {
+		tokens, err := handler.LoadStaticTokenResolver(file)
+		if err != nil {
+			return nil, err
+		}
+
+		resolvers = append(resolvers, tokens)
+	}
+
+	switch len(resolvers) {
+	case 0:
+		return nil, nil
+	case 1:
+	default:
+		return nil, errors.New("set only one of JWT_KEY_FILE, ROLE_HEADER and TOKENS_FILE")
+	}
+
+	if role := os.Getenv("ANONYMOUS_ROLE"); role != "" {
+		return handler.DefaultRole(resolvers[0], role), nil
+	}
+
+	return resolvers[0], nil
 }
@@ -0,0 +1,423 @@
+package parser
+
+import (
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/holdemlab/ui-json-schema/schema"
+)
+
+// Permission path syntax: JSON names joined with dots, "[]" after a
+// collection for its items or map values ("items[].price"), "*" for any
+// single segment and "**" for any number of segments.
+const (
+	pathSeparator   = "."
+	pathItems       = "[]"
+	wildcardSegment = "*"
+	wildcardAny     = "**"
+)
+
+// accessRules holds the resolved permissions of the active roles.
+type accessRules struct {
+	roles  []schema.FieldPermissions
+	policy schema.RolePolicy
+}
+
+// newAccessRules resolves the permissions of the active roles in opts for
+// the struct type t, merging access tags, inherited roles and
+// RolePermissions. A caller without an active role gets the AnyRole
+// entries of the access tags only. It returns nil when no role restricts
+// any field.
+func newAccessRules(t reflect.Type, opts *schema.Options) *accessRules {
+	if opts == nil {
+		return nil
+	}
+
+	tagged := make(map[string]map[string]schema.AccessLevel)
+	collectAccessTags(t, "", tagged, nil)
+
+	active := activeRoles(opts)
+	if len(active) == 0 && len(tagged) == 0 {
+		return nil
+	}
+
+	rules := &accessRules{policy: opts.RolePolicy}
+	restricted := false
+
+	for _, role := range active {
+		perms := resolveRole(role, tagged, opts, make(map[string]bool))
+		fillAnyRole(perms, tagged)
+
+		restricted = restricted || restricts(perms)
+		rules.roles = append(rules.roles, perms)
+	}
+
+	if len(active) == 0 {
+		perms := make(schema.FieldPermissions)
+		fillAnyRole(perms, tagged)
+
+		restricted = restricts(perms)
+		rules.roles = append(rules.roles, perms)
+	}
+
+	if !restricted {
+		return nil
+	}
+
+	return rules
+}
+
+// fillAnyRole sets the AnyRole tag level of the paths perms leaves unset.
+func fillAnyRole(perms schema.FieldPermissions, tagged map[string]map[string]schema.AccessLevel) {
+	for path, levels := range tagged {
+		if _, ok := perms[path]; !ok {
+			if level, ok := levels[schema.AnyRole]; ok {
+				perms[path] = level
+			}
+		}
+	}
+}
+
+// level returns the access level of the field at path, combining the
+// levels of the active roles by the role policy.
+func (r *accessRules) level(path string) schema.AccessLevel {
+	var level schema.AccessLevel
+
+	for i, perms := range r.roles {
+		l := accessLevel(path, perms)
+
+		switch {
+		case i == 0:
+			level = l
+		case r.policy == schema.RolePolicyRestrictive:
+			level = max(level, l)
+		default:
+			level = min(level, l)
+		}
+	}
+
+	return level
+}
+
+// restricts reports whether perms limits access to any field.
+func restricts(perms schema.FieldPermissions) bool {
+	for _, level := range perms {
+		if level != schema.AccessReadWrite {
+			return true
+		}
+	}
+
+	return false
+}
+
+// activeRoles returns Role followed by Roles, without blanks or duplicates.
+func activeRoles(opts *schema.Options) []string {
+	var roles []string
+
+	for _, role := range append([]string{opts.Role}, opts.Roles...) {
+		if role != "" && !slices.Contains(roles, role) {
+			roles = append(roles, role)
+		}
+	}
+
+	return roles
+}
+
+// resolveRole returns the permissions of role: those of the roles it
+// inherits, overridden by its access tag entries, overridden by its own
+// RolePermissions. seen guards against inheritance cycles.
+func resolveRole(role string, tagged map[string]map[string]schema.AccessLevel, opts *schema.Options, seen map[string]bool) schema.FieldPermissions {
+	perms := make(schema.FieldPermissions)
+	if seen[role] {
+		return perms
+	}
+
+	seen[role] = true
+	defer delete(seen, role)
+
+	for _, parent := range opts.RoleInherits[role] {
+		maps.Copy(perms, resolveRole(parent, tagged, opts, seen))
+	}
+
+	for path, levels := range tagged {
+		if level, ok := levels[role]; ok {
+			perms[path] = level
+		}
+	}
+
+	maps.Copy(perms, opts.RolePermissions[role])
+
+	return perms
+}
+
+// collectAccessTags records the access tags of the fields of t by data
+// path, descending into nested structs, array items and map values.
+// stack holds the struct types being walked, to stop on recursive types.
+func collectAccessTags(t reflect.Type, prefix string, tagged map[string]map[string]schema.AccessLevel, stack []reflect.Type) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch t.Kind() { //nolint:exhaustive // only containers hold fields
+	case reflect.Slice, reflect.Array, reflect.Map:
+		if prefix != "" {
+			collectAccessTags(t.Elem(), prefix+pathItems, tagged, stack)
+		}
+	case reflect.Struct:
+		if t == timeType || slices.Contains(stack, t) {
+			return
+		}
+
+		for i := range t.NumField() {
+			field := t.Field(i)
+
+			name := fieldJSONName(field)
+			if !field.IsExported() || name == "-" {
+				continue
+			}
+
+			path := joinPath(prefix, name)
+
+			if access := schema.ParseFieldTags(field).Access; access != nil {
+				tagged[path] = access
+			}
+
+			collectAccessTags(field.Type, path, tagged, append(stack, t))
+		}
+	}
+}
+
+// accessLevel returns the effective access level of the field at path:
+// the most restrictive level of the field and its ancestors, so children
+// of a read-only or hidden struct or collection inherit its level.
+func accessLevel(path string, perms schema.FieldPermissions) schema.AccessLevel {
+	level := schema.AccessReadWrite
+
+	for _, p := range ancestorPaths(path) {
+		level = max(level, ownAccess(p, perms))
+	}
+
+	return level
+}
+
+// ancestorPaths returns path and its ancestors, outermost first:
+// "items[].price" → "items", "items[]", "items[].price".
+func ancestorPaths(path string) []string {
+	var paths []string
+
+	prefix := ""
+
+	for _, segment := range strings.Split(path, pathSeparator) {
+		name := strings.TrimRight(segment, "[]")
+		if prefix != "" {
+			name = prefix + pathSeparator + name
+		}
+
+		paths = append(paths, name)
+
+		for suffix := segment[len(strings.TrimRight(segment, "[]")):]; suffix != ""; suffix = suffix[len(pathItems):] {
+			name += pathItems
+			paths = append(paths, name)
+		}
+
+		prefix = name
+	}
+
+	return paths
+}
+
+// ownAccess returns the level set for path itself: an exact entry wins,
+// otherwise the most restrictive matching wildcard entry.
+func ownAccess(path string, perms schema.FieldPermissions) schema.AccessLevel {
+	if level, ok := perms[path]; ok {
+		return level
+	}
+
+	level := schema.AccessReadWrite
+
+	for pattern, l := range perms {
+		if strings.Contains(pattern, wildcardSegment) && matchPermission(pattern, path) {
+			level = max(level, l)
+		}
+	}
+
+	return level
+}
+
+// matchPermission reports whether a permission pattern matches path.
+func matchPermission(pattern, path string) bool {
+	return matchSegments(strings.Split(pattern, pathSeparator), strings.Split(path, pathSeparator))
+}
+
+// matchSegments matches pattern segments against path segments.
+func matchSegments(pattern, path []string) bool {
+	if len(pattern) == 0 {
+		return len(path) == 0
+	}
+
+	if pattern[0] == wildcardAny {
+		for i := 0; i <= len(path); i++ {
+			if matchSegments(pattern[1:], path[i:]) {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	if len(path) == 0 || (pattern[0] != wildcardSegment && pattern[0] != path[0]) {
+		return false
+	}
+
+	return matchSegments(pattern[1:], path[1:])
+}
+
+// joinPath appends a segment to a permission path.
+func joinPath(prefix, segment string) string {
+	if prefix == "" {
+		return segment
+	}
+
+	if segment == "" {
+		return prefix
+	}
+
+	return prefix + pathSeparator + segment
+}
+
+// applySchemaAccess applies the role permissions in opts to a generated
+// JSON Schema: hidden properties are removed along with their required
+// entries and messages, read-only ones are marked readOnly.
+func applySchemaAccess(s *schema.JSONSchema, t reflect.Type, opts *schema.Options) {
+	if rules := newAccessRules(t, opts); rules != nil {
+		applySchemaAccessAt(s, "", rules)
+	}
+}
+
+// applySchemaAccessAt applies permissions to the properties, items, map
+// values and oneOf branches of s, whose data path is prefix.
+func applySchemaAccessAt(s *schema.JSONSchema, prefix string, rules *accessRules) {
+	if s == nil {
+		return
+	}
+
+	for name, prop := range s.Properties {
+		path := joinPath(prefix, name)
+
+		switch rules.level(path) {
+		case schema.AccessHidden:
+			removeProperty(s, name)
+			continue
+		case schema.AccessReadOnly:
+			prop.ReadOnly = true
+		case schema.AccessReadWrite:
+		}
+
+		applySchemaAccessAt(prop, path, rules)
+	}
+
+	if prefix != "" {
+		applySchemaAccessAt(s.Items, prefix+pathItems, rules)
+		applySchemaAccessAt(s.AdditionalProperties, prefix+pathItems, rules)
+	}
+
+	for _, branch := range s.OneOf {
+		applySchemaAccessAt(branch, prefix, rules)
+	}
+}
+
+// removeProperty removes a property with its required entry and its
+// required error message.
+func removeProperty(s *schema.JSONSchema, name string) {
+	delete(s.Properties, name)
+
+	s.Required = slices.DeleteFunc(s.Required, func(n string) bool { return n == name })
+	if len(s.Required) == 0 {
+		s.Required = nil
+	}
+
+	if required, ok := s.ErrorMessage[errKeywordRequired].(map[string]any); ok {
+		delete(required, name)
+
+		if len(required) == 0 {
+			delete(s.ErrorMessage, errKeywordRequired)
+		}
+
+		if len(s.ErrorMessage) == 0 {
+			s.ErrorMessage = nil
+		}
+	}
+}
+
+// applyUIAccess applies the role permissions in opts to a generated UI
+// Schema: controls of hidden fields are removed, those of read-only fields
+// get options.readonly. Layouts left empty by the removal are dropped.
+func applyUIAccess(root *schema.UISchemaElement, t reflect.Type, opts *schema.Options) {
+	if rules := newAccessRules(t, opts); rules != nil {
+		applyUIAccessAt(root, "", rules)
+	}
+}
+
+// applyUIAccessAt applies permissions to el, whose control scopes are
+// relative to the data path prefix. It reports whether el is kept.
+func applyUIAccessAt(el *schema.UISchemaElement, prefix string, rules *accessRules) bool {
+	if el.Type == "Control" {
+		path := joinPath(prefix, scopeDataPath(el.Scope))
+
+		switch rules.level(path) {
+		case schema.AccessHidden:
+			return false
+		case schema.AccessReadOnly:
+			ensureOptions(el)
+			el.Options["readonly"] = true
+		case schema.AccessReadWrite:
+		}
+
+		if detail, ok := el.Options["detail"].(*schema.UISchemaElement); ok {
+			applyUIAccessAt(detail, path+pathItems, rules)
+		}
+
+		return true
+	}
+
+	if len(el.Elements) == 0 {
+		return true
+	}
+
+	kept := el.Elements[:0]
+
+	for _, child := range el.Elements {
+		if applyUIAccessAt(child, prefix, rules) {
+			kept = append(kept, child)
+		}
+	}
+
+	el.Elements = kept
+
+	return len(kept) > 0
+}
+
+// scopeDataPath converts a JSON Pointer scope to a permission path:
+// "#/properties/contact/properties/email" → "contact.email".
+func scopeDataPath(scope string) string {
+	var path string
+
+	parts := strings.Split(strings.TrimPrefix(scope, "#"), "/")
+
+	for i := 0; i < len(parts); i++ {
+		switch parts[i] {
+		case "":
+		case "properties":
+			if i+1 < len(parts) {
+				i++
+				path = joinPath(path, parts[i])
+			}
+		case "items", "additionalProperties":
+			path += pathItems
+		}
+	}
+
+	return path
+}
@@ -0,0 +1,427 @@
+package parser_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/holdemlab/ui-json-schema/parser"
+	"github.com/holdemlab/ui-json-schema/schema"
+)
+
+type AccessEmployee struct {
+	Name   string `json:"name" required:"true"`
+	Salary int    `json:"salary" required:"true" errmsg:"required=Salary is needed"`
+	Email  string `json:"email" format:"email"`
+	Notes  string `json:"notes" form:"hidden"`
+}
+
+func employeeOptions(role string) schema.Options {
+	opts := schema.DefaultOptions()
+	opts.Role = role
+	opts.RolePermissions = map[string]schema.FieldPermissions{
+		"viewer": {
+			"salary": schema.AccessHidden,
+			"email":  schema.AccessReadOnly,
+		},
+	}
+
+	return opts
+}
+
+func TestJSONSchema_RoleHiddenPruned(t *testing.T) {
+	s, err := parser.GenerateJSONSchemaWithOptions(AccessEmployee{}, employeeOptions("viewer"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, ok := s.Properties["salary"]; ok {
+		t.Error("expected hidden salary to be pruned from the JSON Schema")
+	}
+
+	if !reflect.DeepEqual(s.Required, []string{"name"}) {
+		t.Errorf("expected salary removed from required, got %v", s.Required)
+	}
+
+	if s.ErrorMessage != nil {
+		t.Errorf("expected no required message for a pruned field, got %v", s.ErrorMessage)
+	}
+
+	if !s.Properties["email"].ReadOnly {
+		t.Error("expected email to be readOnly")
+	}
+
+	if s.Properties["name"].ReadOnly {
+		t.Error("expected name to stay writable")
+	}
+
+	// form:"hidden" only hides the control; the data is still described.
+	if _, ok := s.Properties["notes"]; !ok {
+		t.Error("expected form-hidden notes to stay in the JSON Schema")
+	}
+}
+
+type AccessTeam struct {
+	Lead    AccessEmployee   `json:"lead"`
+	Members []AccessEmployee `json:"members"`
+}
+
+func teamOptions(perms schema.FieldPermissions) schema.Options {
+	opts := schema.DefaultOptions()
+	opts.Role = "viewer"
+	opts.RolePermissions = map[string]schema.FieldPermissions{"viewer": perms}
+
+	return opts
+}
+
+func TestJSONSchema_RolePermissionsNested(t *testing.T) {
+	s, err := parser.GenerateJSONSchemaWithOptions(AccessTeam{}, teamOptions(schema.FieldPermissions{
+		"lead.salary":     schema.AccessHidden,
+		"members[].email": schema.AccessReadOnly,
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	lead := s.Properties["lead"]
+	if _, ok := lead.Properties["salary"]; ok || len(lead.Required) != 1 {
+		t.Errorf("expected salary pruned in nested struct, got %v / %v", lead.Properties, lead.Required)
+	}
+
+	if !s.Properties["members"].Items.Properties["email"].ReadOnly {
+		t.Error("expected email readOnly in array items")
+	}
+
+	if s.Properties["lead"].Properties["email"].ReadOnly {
+		t.Error("expected lead.email to be unaffected by members[].email")
+	}
+
+	if _, ok := s.Properties["members"].Items.Properties["salary"]; !ok {
+		t.Error("expected members[].salary to be unaffected by lead.salary")
+	}
+}
+
+func TestJSONSchema_BareNameMatchesRootOnly(t *testing.T) {
+	s, err := parser.GenerateJSONSchemaWithOptions(AccessTeam{}, teamOptions(schema.FieldPermissions{
+		"salary": schema.AccessHidden,
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, ok := s.Properties["lead"].Properties["salary"]; !ok {
+		t.Error("expected a root-level permission not to apply to nested fields")
+	}
+}
+
+func TestJSONSchema_WildcardPermissions(t *testing.T) {
+	s, err := parser.GenerateJSONSchemaWithOptions(AccessTeam{}, teamOptions(schema.FieldPermissions{
+		"*.salary": schema.AccessHidden,
+		"**.email": schema.AccessReadOnly,
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, ok := s.Properties["lead"].Properties["salary"]; ok {
+		t.Error("expected *.salary to hide lead.salary")
+	}
+
+	// "*" matches one segment, including a collection segment "members[]".
+	if _, ok := s.Properties["members"].Items.Properties["salary"]; ok {
+		t.Error("expected *.salary to hide members[].salary")
+	}
+
+	if s.Properties["lead"].Properties["name"].ReadOnly {
+		t.Error("expected lead.name to stay editable")
+	}
+
+	if !s.Properties["lead"].Properties["email"].ReadOnly || !s.Properties["members"].Items.Properties["email"].ReadOnly {
+		t.Error("expected **.email to match at any depth")
+	}
+}
+
+func TestJSONSchema_InheritedPermissions(t *testing.T) {
+	s, err := parser.GenerateJSONSchemaWithOptions(AccessTeam{}, teamOptions(schema.FieldPermissions{
+		"lead":         schema.AccessReadOnly,
+		"lead.email":   schema.AccessReadWrite,
+		"lead.salary":  schema.AccessHidden,
+		"members[]":    schema.AccessHidden,
+		"members.name": schema.AccessReadOnly,
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	lead := s.Properties["lead"]
+	if !lead.ReadOnly || !lead.Properties["name"].ReadOnly {
+		t.Error("expected children of a read-only struct to be read-only")
+	}
+
+	if !lead.Properties["email"].ReadOnly {
+		t.Error("expected a child not to be more permissive than its parent")
+	}
+
+	if _, ok := lead.Properties["salary"]; ok {
+		t.Error("expected a child to be more restrictive than its parent")
+	}
+
+	if items := s.Properties["members"].Items; len(items.Properties) != 0 {
+		t.Errorf("expected hidden items to prune every item property, got %v", items.Properties)
+	}
+}
+
+func TestUISchema_PathPermissions(t *testing.T) {
+	ui, err := parser.GenerateUISchemaWithOptions(AccessTeam{}, teamOptions(schema.FieldPermissions{
+		"lead.salary":     schema.AccessHidden,
+		"lead.email":      schema.AccessReadOnly,
+		"members[].email": schema.AccessHidden,
+		"members[].name":  schema.AccessReadOnly,
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	lead := ui.Elements[0]
+	scopes := map[string]*schema.UISchemaElement{}
+
+	for _, el := range lead.Elements {
+		scopes[el.Scope] = el
+	}
+
+	if _, ok := scopes["#/properties/lead/properties/salary"]; ok {
+		t.Error("expected lead.salary control to be removed")
+	}
+
+	if el := scopes["#/properties/lead/properties/email"]; el == nil || el.Options["readonly"] != true {
+		t.Errorf("expected lead.email to be readonly, got %+v", el)
+	}
+
+	if el := scopes["#/properties/lead/properties/name"]; el == nil || el.Options["readonly"] == true {
+		t.Errorf("expected lead.name untouched, got %+v", el)
+	}
+
+	detail, ok := ui.Elements[1].Options["detail"].(*schema.UISchemaElement)
+	if !ok {
+		t.Fatalf("expected members detail, got %v", ui.Elements[1].Options)
+	}
+
+	for _, el := range detail.Elements {
+		if el.Scope == "#/properties/email" {
+			t.Error("expected members[].email to be removed from the detail")
+		}
+
+		if el.Scope == "#/properties/name" && el.Options["readonly"] != true {
+			t.Error("expected members[].name to be readonly in the detail")
+		}
+	}
+}
+
+func TestUISchema_HiddenStructDropsGroup(t *testing.T) {
+	ui, err := parser.GenerateUISchemaWithOptions(AccessTeam{}, teamOptions(schema.FieldPermissions{
+		"lead": schema.AccessHidden,
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(ui.Elements) != 1 || ui.Elements[0].Scope != "#/properties/members" {
+		t.Errorf("expected only the members control, got %+v", ui.Elements)
+	}
+}
+
+func TestJSONSchema_NoRoleKeepsFields(t *testing.T) {
+	s, err := parser.GenerateJSONSchemaWithOptions(AccessEmployee{}, employeeOptions(""))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, ok := s.Properties["salary"]; !ok || len(s.Required) != 2 {
+		t.Errorf("expected all fields without an active role, got %v / %v", s.Properties, s.Required)
+	}
+
+	if s.Properties["email"].ReadOnly {
+		t.Error("expected no readOnly without an active role")
+	}
+}
+
+type AccessPayroll struct {
+	Name    string           `json:"name"`
+	Salary  int              `json:"salary" access:"hr=rw;manager=ro;*=hidden"`
+	Bonus   int              `json:"bonus" access:"hr=ro"`
+	Reports []AccessPayslip  `json:"reports"`
+	Next    *AccessPayroll   `json:"-"`
+	Extra   map[string]int64 `json:"extra"`
+}
+
+type AccessPayslip struct {
+	Month string `json:"month"`
+	Net   int    `json:"net" access:"*=hidden;hr=rw"`
+}
+
+func payrollSchema(t *testing.T, opts schema.Options) *schema.JSONSchema {
+	t.Helper()
+
+	s, err := parser.GenerateJSONSchemaWithOptions(AccessPayroll{}, opts)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	return s
+}
+
+func TestJSONSchema_AccessTag(t *testing.T) {
+	tests := []struct {
+		role              string
+		salary, net       bool
+		salaryRO, bonusRO bool
+	}{
+		{"hr", true, true, false, true},
+		{"manager", true, false, true, false},
+		{"guest", false, false, false, false},
+	}
+
+	for _, tt := range tests {
+		opts := schema.DefaultOptions()
+		opts.Role = tt.role
+		s := payrollSchema(t, opts)
+
+		salary, ok := s.Properties["salary"]
+		if ok != tt.salary {
+			t.Errorf("%s: salary present = %v, want %v", tt.role, ok, tt.salary)
+		}
+
+		if ok && salary.ReadOnly != tt.salaryRO {
+			t.Errorf("%s: salary readOnly = %v, want %v", tt.role, salary.ReadOnly, tt.salaryRO)
+		}
+
+		if _, ok := s.Properties["reports"].Items.Properties["net"]; ok != tt.net {
+			t.Errorf("%s: reports[].net present = %v, want %v", tt.role, ok, tt.net)
+		}
+
+		if s.Properties["bonus"].ReadOnly != tt.bonusRO {
+			t.Errorf("%s: bonus readOnly = %v, want %v", tt.role, s.Properties["bonus"].ReadOnly, tt.bonusRO)
+		}
+	}
+}
+
+func TestAccessTag_AnyRoleWithoutRole(t *testing.T) {
+	// A caller without a role gets the "*" entries of the access tags.
+	s := payrollSchema(t, schema.DefaultOptions())
+
+	if _, ok := s.Properties["salary"]; ok {
+		t.Error("expected salary hidden from the JSON Schema without a role")
+	}
+
+	if _, ok := s.Properties["reports"].Items.Properties["net"]; ok {
+		t.Error("expected reports[].net hidden from the JSON Schema without a role")
+	}
+
+	if s.Properties["bonus"].ReadOnly {
+		t.Error("expected bonus writable without a role")
+	}
+
+	ui, err := parser.GenerateUISchemaWithOptions(AccessPayroll{}, schema.DefaultOptions())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, el := range ui.Elements {
+		if el.Scope == "#/properties/salary" {
+			t.Error("expected salary hidden from the UI Schema without a role")
+		}
+	}
+}
+
+func TestJSONSchema_OptionsOverrideAccessTag(t *testing.T) {
+	opts := schema.DefaultOptions()
+	opts.Role = "auditor"
+	opts.RolePermissions = map[string]schema.FieldPermissions{
+		"auditor": {"salary": schema.AccessReadOnly},
+	}
+
+	s := payrollSchema(t, opts)
+	if salary, ok := s.Properties["salary"]; !ok || !salary.ReadOnly {
+		t.Errorf("expected RolePermissions to override the tag default, got %+v", salary)
+	}
+}
+
+func TestJSONSchema_MultipleRoles(t *testing.T) {
+	opts := schema.DefaultOptions()
+	opts.Role = "manager"
+	opts.Roles = []string{"guest", "manager"}
+	opts.RolePermissions = map[string]schema.FieldPermissions{
+		"guest": {"name": schema.AccessReadOnly},
+	}
+
+	s := payrollSchema(t, opts)
+	if salary, ok := s.Properties["salary"]; !ok || !salary.ReadOnly {
+		t.Errorf("expected the permissive policy to grant the manager level, got %+v", salary)
+	}
+
+	if s.Properties["name"].ReadOnly {
+		t.Error("expected manager full access to name to win")
+	}
+
+	opts.RolePolicy = schema.RolePolicyRestrictive
+
+	s = payrollSchema(t, opts)
+	if _, ok := s.Properties["salary"]; ok {
+		t.Error("expected the restrictive policy to hide salary")
+	}
+
+	if !s.Properties["name"].ReadOnly {
+		t.Error("expected the restrictive policy to keep name read-only")
+	}
+}
+
+func TestJSONSchema_RoleInheritance(t *testing.T) {
+	opts := schema.DefaultOptions()
+	opts.Role = "lead"
+	opts.RoleInherits = map[string][]string{
+		"lead":    {"staff", "manager"},
+		"staff":   {"lead"}, // cycles are ignored
+		"manager": nil,
+	}
+	opts.RolePermissions = map[string]schema.FieldPermissions{
+		"staff": {"name": schema.AccessReadOnly, "extra": schema.AccessHidden},
+		"lead":  {"extra": schema.AccessReadWrite},
+	}
+
+	s := payrollSchema(t, opts)
+
+	if !s.Properties["name"].ReadOnly {
+		t.Error("expected name read-only inherited from staff")
+	}
+
+	if salary, ok := s.Properties["salary"]; !ok || !salary.ReadOnly {
+		t.Errorf("expected salary read-only inherited from the manager access tag, got %+v", salary)
+	}
+
+	if _, ok := s.Properties["extra"]; !ok {
+		t.Error("expected the own permission of lead to override staff")
+	}
+}
+
+func TestUISchema_AccessTag(t *testing.T) {
+	opts := schema.DefaultOptions()
+	opts.Role = "manager"
+
+	ui, err := parser.GenerateUISchemaWithOptions(AccessPayroll{}, opts)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, el := range ui.Elements {
+		if el.Scope == "#/properties/salary" && el.Options["readonly"] != true {
+			t.Error("expected salary readonly for manager")
+		}
+
+		if detail, ok := el.Options["detail"].(*schema.UISchemaElement); ok {
+			for _, d := range detail.Elements {
+				if d.Scope == "#/properties/net" {
+					t.Error("expected reports[].net removed from the detail")
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package parser
+
+import "github.com/holdemlab/ui-json-schema/schema"
+
+// otherCategoryName returns the name of the fallback category for
+// uncategorized elements.
+func otherCategoryName(opts *schema.Options) string {
+	if opts != nil && opts.Categorization.OtherLabel != "" {
+		return opts.Categorization.OtherLabel
+	}
+
+	return otherCategoryLabel
+}
+
+// newOtherCategory creates the fallback category for uncategorized elements
+// with its label localized.
+func newOtherCategory(opts *schema.Options) *schema.UISchemaElement {
+	cat := schema.NewCategory(otherCategoryName(opts))
+	localizeOtherCategory(cat, opts)
+
+	return cat
+}
+
+// localizeOtherCategory translates the fallback category label, using
+// Options.Categorization.OtherI18nKey when set and the label itself otherwise.
+func localizeOtherCategory(cat *schema.UISchemaElement, opts *schema.Options) {
+	var key string
+	if opts != nil {
+		key = opts.Categorization.OtherI18nKey
+	}
+
+	if key != "" {
+		cat.I18n = key
+	}
+
+	cat.Label = translateLabel(cat.Label, key, opts)
+	if cat.Label == "" {
+		cat.Label = otherCategoryName(opts)
+	}
+}
+
+// sortCategoryNames orders category names: names listed in order come
+// first in that order, the rest keep their first-seen order.
+func sortCategoryNames(seen, order []string) []string {
+	if len(order) == 0 {
+		return seen
+	}
+
+	present := make(map[string]bool, len(seen))
+	for _, name := range seen {
+		present[name] = true
+	}
+
+	result := make([]string, 0, len(seen))
+	listed := make(map[string]bool, len(order))
+
+	for _, name := range order {
+		if present[name] && !listed[name] {
+			result = append(result, name)
+			listed[name] = true
+		}
+	}
+
+	for _, name := range seen {
+		if !listed[name] {
+			result = append(result, name)
+		}
+	}
+
+	return result
+}
+
+// applyCategorizationOptions sets the Categorization-level options
+// (variant, showNavButtons) configured in Options.Categorization.
+// Options already present on the element are kept.
+func applyCategorizationOptions(categorization *schema.UISchemaElement, opts *schema.Options) {
+	if opts == nil {
+		return
+	}
+
+	if opts.Categorization.Variant != "" {
+		setDefaultOption(categorization, "variant", opts.Categorization.Variant)
+	}
+
+	if opts.Categorization.ShowNavButtons {
+		setDefaultOption(categorization, "showNavButtons", true)
+	}
+}
@@ -0,0 +1,317 @@
+package parser_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/holdemlab/ui-json-schema/parser"
+	"github.com/holdemlab/ui-json-schema/schema"
+)
+
+type WizardForm struct {
+	Notes   string `json:"notes"`
+	Name    string `json:"name" form:"category=Personal"`
+	Email   string `json:"email" form:"category=Contact"`
+	Country string `json:"country" form:"category=Address"`
+}
+
+// categoryLabels returns the labels of the categories of a Categorization.
+func categoryLabels(ui *schema.UISchemaElement) []string {
+	labels := make([]string, 0, len(ui.Elements))
+	for _, cat := range ui.Elements {
+		labels = append(labels, cat.Label)
+	}
+
+	return labels
+}
+
+func assertLabels(t *testing.T, got, want []string) {
+	t.Helper()
+
+	if len(got) != len(want) {
+		t.Fatalf("expected categories %v, got %v", want, got)
+	}
+
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("expected categories %v, got %v", want, got)
+		}
+	}
+}
+
+func TestCategorization_DefaultOther(t *testing.T) {
+	ui, err := parser.GenerateUISchema(WizardForm{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	assertLabels(t, categoryLabels(ui), []string{"Other", "Personal", "Contact", "Address"})
+}
+
+func TestCategorization_OtherLabelTranslated(t *testing.T) {
+	opts := schema.DefaultOptions()
+	opts.Translator = schema.NewMapTranslator(map[string]map[string]string{
+		"uk": {"Other": "Інше", "category.misc": "Різне"},
+	})
+	opts.Locale = "uk"
+
+	ui, err := parser.GenerateUISchemaWithOptions(WizardForm{}, opts)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if ui.Elements[0].Label != "Інше" {
+		t.Errorf("expected translated fallback label, got %q", ui.Elements[0].Label)
+	}
+
+	opts.Categorization.OtherI18nKey = "category.misc"
+
+	ui, err = parser.GenerateUISchemaWithOptions(WizardForm{}, opts)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	other := ui.Elements[0]
+	if other.Label != "Різне" || other.I18n != "category.misc" {
+		t.Errorf("expected fallback translated via i18n key, got label=%q i18n=%q", other.Label, other.I18n)
+	}
+}
+
+func TestCategorization_OtherLabelRenamed(t *testing.T) {
+	opts := schema.DefaultOptions()
+	opts.Categorization.OtherLabel = "General"
+
+	ui, err := parser.GenerateUISchemaWithOptions(WizardForm{}, opts)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if ui.Elements[0].Label != "General" {
+		t.Errorf("expected renamed fallback category, got %q", ui.Elements[0].Label)
+	}
+}
+
+func TestCategorization_OmitOther(t *testing.T) {
+	opts := schema.DefaultOptions()
+	opts.Categorization.OmitOther = true
+
+	ui, err := parser.GenerateUISchemaWithOptions(WizardForm{}, opts)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	assertLabels(t, categoryLabels(ui), []string{"Personal", "Contact", "Address"})
+}
+
+func TestCategorization_Order(t *testing.T) {
+	opts := schema.DefaultOptions()
+	opts.Categorization.Order = []string{"Address", "Personal", "Missing"}
+
+	ui, err := parser.GenerateUISchemaWithOptions(WizardForm{}, opts)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	assertLabels(t, categoryLabels(ui), []string{"Address", "Personal", "Other", "Contact"})
+}
+
+func TestCategorization_StepperAndCategoryOptions(t *testing.T) {
+	opts := schema.DefaultOptions()
+	opts.Categorization = schema.CategorizationOptions{
+		OmitOther:      true,
+		Variant:        "stepper",
+		ShowNavButtons: true,
+		Categories: map[string]map[string]any{
+			"Personal": {"icon": "person", "description": "Who you are"},
+		},
+	}
+
+	ui, err := parser.GenerateUISchemaWithOptions(WizardForm{}, opts)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	data, err := json.Marshal(ui)
+	if err != nil {
+		t.Fatalf("marshal error: %v", err)
+	}
+
+	var raw struct {
+		Options  map[string]any `json:"options"`
+		Elements []struct {
+			Label   string         `json:"label"`
+			Options map[string]any `json:"options"`
+		} `json:"elements"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		t.Fatalf("unmarshal error: %v", err)
+	}
+
+	if raw.Options["variant"] != "stepper" || raw.Options["showNavButtons"] != true {
+		t.Errorf("expected stepper options, got %v", raw.Options)
+	}
+
+	personal := raw.Elements[0]
+	if personal.Options["icon"] != "person" || personal.Options["description"] != "Who you are" {
+		t.Errorf("expected per-category options, got %v", personal.Options)
+	}
+
+	if raw.Elements[1].Options != nil {
+		t.Errorf("expected no options on Contact, got %v", raw.Elements[1].Options)
+	}
+}
+
+func TestCategorization_TemplateUsesOptions(t *testing.T) {
+	opts := schema.DefaultOptions()
+	opts.Categorization.OtherLabel = "More"
+	opts.Categorization.Variant = "stepper"
+	opts.Template = &schema.UISchemaTemplate{
+		Type: "Categorization",
+		Elements: []*schema.UISchemaTemplate{
+			{Type: "Category", Label: "Main", Elements: []*schema.UISchemaTemplate{{Field: "name"}}},
+		},
+	}
+
+	ui, err := parser.GenerateUISchemaWithOptions(WizardForm{}, opts)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	assertLabels(t, categoryLabels(ui), []string{"Main", "More"})
+
+	if ui.Options["variant"] != "stepper" {
+		t.Errorf("expected stepper variant on template Categorization, got %v", ui.Options)
+	}
+
+	opts.Categorization.OmitOther = true
+
+	ui, err = parser.GenerateUISchemaWithOptions(WizardForm{}, opts)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	assertLabels(t, categoryLabels(ui), []string{"Main"})
+}
+
+type NestedCategorizedProfile struct {
+	Name  string `json:"name" form:"category=Basics"`
+	Phone string `json:"phone" form:"category=Contact;layout=horizontal"`
+	Email string `json:"email" form:"category=Contact;layout=horizontal"`
+}
+
+type NestedCategorizedItem struct {
+	SKU   string  `json:"sku" form:"category=Product"`
+	Price float64 `json:"price" form:"category=Pricing;visibleIf=sku:set"`
+}
+
+type NestedCategorizedForm struct {
+	Title   string                   `json:"title"`
+	Profile NestedCategorizedProfile `json:"profile" form:"label=Profile"`
+	Items   []NestedCategorizedItem  `json:"items"`
+}
+
+func TestCategorization_NestedStruct(t *testing.T) {
+	ui, err := parser.GenerateUISchema(NestedCategorizedForm{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Inner categories must not turn the root into a Categorization.
+	if ui.Type != "VerticalLayout" {
+		t.Fatalf("expected root VerticalLayout, got %s", ui.Type)
+	}
+
+	group := ui.Elements[1]
+	if group.Type != "Group" || len(group.Elements) != 1 {
+		t.Fatalf("expected Group wrapping one Categorization, got %+v", group)
+	}
+
+	inner := group.Elements[0]
+	if inner.Type != "Categorization" {
+		t.Fatalf("expected nested Categorization, got %s", inner.Type)
+	}
+
+	assertLabels(t, categoryLabels(inner), []string{"Basics", "Contact"})
+
+	contact := inner.Elements[1]
+	if len(contact.Elements) != 1 || contact.Elements[0].Type != "HorizontalLayout" {
+		t.Errorf("expected HorizontalLayout inside Contact, got %+v", contact.Elements)
+	}
+}
+
+func TestCategorization_ArrayDetail(t *testing.T) {
+	ui, err := parser.GenerateUISchema(NestedCategorizedForm{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	items := ui.Elements[2]
+
+	detail, ok := items.Options["detail"].(*schema.UISchemaElement)
+	if !ok {
+		t.Fatalf("expected detail on items, got %v", items.Options)
+	}
+
+	if detail.Type != "Categorization" {
+		t.Fatalf("expected Categorization detail, got %s", detail.Type)
+	}
+
+	assertLabels(t, categoryLabels(detail), []string{"Product", "Pricing"})
+
+	pricing := detail.Elements[1]
+	if pricing.Rule == nil || pricing.Rule.Condition.Scope != "#/properties/sku" {
+		t.Errorf("expected category rule on Pricing, got %+v", pricing.Rule)
+	}
+}
+
+func TestCategorization_OuterAndInner(t *testing.T) {
+	type Form struct {
+		Name    string                   `json:"name" form:"category=Main"`
+		Profile NestedCategorizedProfile `json:"profile" form:"category=Details"`
+	}
+
+	ui, err := parser.GenerateUISchema(Form{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	assertLabels(t, categoryLabels(ui), []string{"Main", "Details"})
+
+	group := ui.Elements[1].Elements[0]
+	if group.Type != "Group" || group.Options != nil {
+		t.Fatalf("expected Group without hints in Details, got %+v", group)
+	}
+
+	if group.Elements[0].Type != "Categorization" {
+		t.Errorf("expected the group to keep its own Categorization, got %s", group.Elements[0].Type)
+	}
+}
+
+func TestCategorization_NoInternalOptionsLeak(t *testing.T) {
+	type Item struct {
+		Code string `json:"code" form:"visibleIf=active:true;i18n=item.code"`
+	}
+
+	type Form struct {
+		Active bool   `json:"active" form:"widget=none"`
+		Note   string `json:"note" form:"hideIf=active:false"`
+		Items  []Item `json:"items"`
+	}
+
+	ui, err := parser.GenerateUISchema(Form{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	data, err := json.Marshal(ui)
+	if err != nil {
+		t.Fatalf("marshal error: %v", err)
+	}
+
+	for _, hint := range []string{"category", "categoryRuleEffect", "categoryRuleExpr", "categoryI18n", "layoutGroup"} {
+		if strings.Contains(string(data), `"`+hint+`"`) {
+			t.Errorf("internal hint %q leaked into UI Schema: %s", hint, data)
+		}
+	}
+}
@@ -0,0 +1,305 @@
+package parser
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/holdemlab/ui-json-schema/schema"
+)
+
+// ErrInvalidData is wrapped by the ValidationErrors returned by Decode.
+var ErrInvalidData = errors.New("submitted data is invalid")
+
+// FieldError is a validation error located by Go field path.
+type FieldError struct {
+	// Field is the Go path of the invalid value, e.g. "Lines[0].Price" or
+	// `Meta["key"]`; "" for the root. A missing required property is
+	// located at its own field.
+	Field string `json:"field"`
+	schema.ValidationError
+}
+
+// Error implements the error interface.
+func (e FieldError) Error() string {
+	if e.Field == "" {
+		return e.Message
+	}
+
+	return e.Field + ": " + e.Message
+}
+
+// ValidationErrors lists the violations found by Decode. It wraps
+// ErrInvalidData.
+type ValidationErrors []FieldError
+
+// Error implements the error interface.
+func (e ValidationErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, fe := range e {
+		messages[i] = fe.Error()
+	}
+
+	return ErrInvalidData.Error() + ": " + strings.Join(messages, "; ")
+}
+
+// Unwrap returns ErrInvalidData.
+func (e ValidationErrors) Unwrap() error {
+	return ErrInvalidData
+}
+
+// Decode decodes submitted JSON form data into v, a pointer to a struct.
+// Keys are first matched to the properties of the JSON Schema generated for
+// v's type with opts as encoding/json matches fields (exactly, else
+// case-insensitively) and renamed to them; keys matching none, including
+// fields hidden for the active roles, are dropped, so only validated data
+// is decoded. Values of default tags are filled in for missing fields and
+// the result is validated against the schema (see schema.Validate).
+// Missing nested structs are not validated; they receive the defaults of
+// their fields after validation succeeds.
+// When validation fails v is left unchanged and the returned
+// ValidationErrors locate each error by Go field path.
+func Decode(data []byte, v any, opts schema.Options) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("%w: %T is not a pointer to a struct", ErrNotStruct, v)
+	}
+
+	t := rv.Elem().Type()
+
+	var value any
+	if err := json.Unmarshal(data, &value); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidJSON, err) //nolint:errorlint // wrapping intentional
+	}
+
+	if _, ok := value.(map[string]any); !ok {
+		return ErrNotJSONObject
+	}
+
+	// The schema describes the type, not the values of v.
+	opts.OmitEmpty, opts.ValueDefaults = false, false
+
+	s, err := GenerateJSONSchemaWithOptions(reflect.New(t).Interface(), opts)
+	if err != nil {
+		return err
+	}
+
+	obj, _ := canonicalKeys(s, value).(map[string]any)
+	applyDefaults(t, obj, false)
+
+	if errs := schema.Validate(s, obj); errs != nil {
+		result := make(ValidationErrors, len(errs))
+		for i, e := range errs {
+			result[i] = FieldError{Field: goFieldPath(t, e), ValidationError: e}
+		}
+
+		return result
+	}
+
+	// Missing nested structs are not validated, so they may be filled now.
+	applyDefaults(t, obj, true)
+
+	filled, err := json.Marshal(obj)
+	if err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal(filled, v); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidJSON, err) //nolint:errorlint // wrapping intentional
+	}
+
+	return nil
+}
+
+// canonicalKeys returns a copy of value keeping only what s declares.
+// Object keys are renamed to the property they match, exactly or else
+// case-insensitively; an exact match wins, and among case variants the
+// last in sorted order, as no document order is left. Keys matching no
+// property are dropped unless s is a map or a free-form object.
+func canonicalKeys(s *schema.JSONSchema, value any) any {
+	if s == nil {
+		return value
+	}
+
+	switch val := value.(type) {
+	case map[string]any:
+		return canonicalObject(s, val)
+	case []any:
+		items := make([]any, len(val))
+		for i, item := range val {
+			items[i] = canonicalKeys(s.Items, item)
+		}
+
+		return items
+	default:
+		return value
+	}
+}
+
+// canonicalObject applies canonicalKeys to the properties or map values
+// of obj.
+func canonicalObject(s *schema.JSONSchema, obj map[string]any) map[string]any {
+	keys := make([]string, 0, len(obj))
+	for k := range obj {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	result := make(map[string]any, len(obj))
+
+	for _, key := range keys {
+		name, prop := propertySchema(s, key)
+
+		switch {
+		case prop != nil:
+			if _, exact := obj[name]; !exact || name == key {
+				result[name] = canonicalKeys(prop, obj[key])
+			}
+		case s.AdditionalProperties != nil:
+			result[key] = canonicalKeys(s.AdditionalProperties, obj[key])
+		case s.Properties == nil && len(s.OneOf) == 0:
+			result[key] = obj[key]
+		}
+	}
+
+	return result
+}
+
+// applyDefaults sets the default tag values of the fields of struct type t
+// missing from obj, descending into nested structs, slices and maps. With
+// nested set, missing nested structs (not pointers) are created when they
+// receive defaults.
+func applyDefaults(t reflect.Type, obj map[string]any, nested bool) {
+	for i := range t.NumField() {
+		field := t.Field(i)
+
+		name := fieldJSONName(field)
+		if !field.IsExported() || name == "-" {
+			continue
+		}
+
+		value, ok := obj[name]
+		if !ok {
+			if def := schema.ParseFieldTags(field).Default; def != nil {
+				obj[name] = def
+			} else if nested {
+				if defaults := defaultsOf(field.Type); defaults != nil {
+					obj[name] = defaults
+				}
+			}
+
+			continue
+		}
+
+		applyValueDefaults(field.Type, value, nested)
+	}
+}
+
+// defaultsOf returns the defaults of a missing non-pointer struct field of
+// type t, or nil when it has none.
+func defaultsOf(t reflect.Type) map[string]any {
+	if t.Kind() != reflect.Struct || t == timeType {
+		return nil
+	}
+
+	obj := make(map[string]any)
+	applyDefaults(t, obj, true)
+
+	if len(obj) == 0 {
+		return nil
+	}
+
+	return obj
+}
+
+// applyValueDefaults applies defaults inside a submitted value of type t.
+func applyValueDefaults(t reflect.Type, value any, nested bool) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch val := value.(type) {
+	case map[string]any:
+		switch {
+		case t.Kind() == reflect.Struct && t != timeType:
+			applyDefaults(t, val, nested)
+		case t.Kind() == reflect.Map:
+			for _, item := range val {
+				applyValueDefaults(t.Elem(), item, nested)
+			}
+		}
+	case []any:
+		if t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+			for _, item := range val {
+				applyValueDefaults(t.Elem(), item, nested)
+			}
+		}
+	}
+}
+
+// goFieldPath converts the instance path of a validation error on struct
+// type t to a Go field path. Tokens with no matching field are kept as
+// they are.
+func goFieldPath(t reflect.Type, e schema.ValidationError) string {
+	var tokens []string
+
+	if e.InstancePath != "" {
+		tokens = strings.Split(strings.TrimPrefix(e.InstancePath, "/"), "/")
+	}
+
+	if missing, ok := e.Params["missingProperty"].(string); ok && e.Keyword == "required" {
+		tokens = append(tokens, missing)
+	}
+
+	var b strings.Builder
+
+	for _, token := range tokens {
+		token = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+
+		for t != nil && t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+
+		switch {
+		case t != nil && (t.Kind() == reflect.Slice || t.Kind() == reflect.Array):
+			b.WriteString("[" + token + "]")
+			t = t.Elem()
+		case t != nil && t.Kind() == reflect.Map:
+			b.WriteString("[" + strconv.Quote(token) + "]")
+			t = t.Elem()
+		default:
+			var name string
+			t, name = structField(t, token)
+
+			if b.Len() > 0 {
+				b.WriteString(".")
+			}
+
+			b.WriteString(name)
+		}
+	}
+
+	return b.String()
+}
+
+// structField returns the type and Go name of the field of struct type t
+// with the given JSON name, or nil and the JSON name when there is none.
+func structField(t reflect.Type, name string) (reflect.Type, string) {
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil, name
+	}
+
+	for i := range t.NumField() {
+		field := t.Field(i)
+		if field.IsExported() && fieldJSONName(field) == name {
+			return field.Type, field.Name
+		}
+	}
+
+	return nil, name
+}
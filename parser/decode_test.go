@@ -0,0 +1,161 @@
+package parser_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/holdemlab/ui-json-schema/parser"
+	"github.com/holdemlab/ui-json-schema/schema"
+)
+
+type DecodeAddress struct {
+	City    string `json:"city" required:"true"`
+	Country string `json:"country" default:"UA"`
+}
+
+type DecodeLine struct {
+	SKU string `json:"sku" required:"true"`
+	Qty int    `json:"qty" default:"1" minimum:"1"`
+}
+
+type DecodeOrder struct {
+	Email    string                `json:"email" required:"true" format:"email"`
+	Status   string                `json:"status" default:"new" enum:"new,paid"`
+	Express  bool                  `json:"express" default:"true"`
+	Address  DecodeAddress         `json:"address"`
+	Billing  *DecodeAddress        `json:"billing"`
+	Lines    []DecodeLine          `json:"lines"`
+	Extras   map[string]DecodeLine `json:"extras"`
+	Internal string                `json:"-" default:"x"`
+}
+
+func TestDecode_AppliesDefaults(t *testing.T) {
+	data := `{
+		"email": "a@example.com",
+		"express": false,
+		"lines": [{"sku": "a"}, {"sku": "b", "qty": 3}],
+		"extras": {"gift": {"sku": "g"}}
+	}`
+
+	var order DecodeOrder
+	if err := parser.Decode([]byte(data), &order, schema.DefaultOptions()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := DecodeOrder{
+		Email:   "a@example.com",
+		Status:  "new",
+		Express: false,
+		Address: DecodeAddress{Country: "UA"},
+		Lines:   []DecodeLine{{SKU: "a", Qty: 1}, {SKU: "b", Qty: 3}},
+		Extras:  map[string]DecodeLine{"gift": {SKU: "g", Qty: 1}},
+	}
+
+	if !reflect.DeepEqual(order, expected) {
+		t.Errorf("got %+v, want %+v", order, expected)
+	}
+}
+
+func TestDecode_ValidationErrors(t *testing.T) {
+	data := `{
+		"status": "lost",
+		"address": {"country": "PL"},
+		"billing": {"city": 5},
+		"lines": [{"qty": 0}],
+		"extras": {"a/b": {"sku": "x", "qty": -1}}
+	}`
+
+	order := DecodeOrder{Email: "keep@example.com"}
+
+	err := parser.Decode([]byte(data), &order, schema.DefaultOptions())
+	if !errors.Is(err, parser.ErrInvalidData) {
+		t.Fatalf("expected ErrInvalidData, got %v", err)
+	}
+
+	var verrs parser.ValidationErrors
+	if !errors.As(err, &verrs) {
+		t.Fatalf("expected ValidationErrors, got %T", err)
+	}
+
+	got := make([]string, len(verrs))
+	for i, e := range verrs {
+		got[i] = e.Field + " " + e.Keyword
+	}
+
+	expected := []string{
+		"Email required",
+		"Address.City required",
+		"Billing.City type",
+		`Extras["a/b"].Qty minimum`,
+		"Lines[0].SKU required",
+		"Lines[0].Qty minimum",
+		"Status enum",
+	}
+
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("got %v, want %v", got, expected)
+	}
+
+	if verrs[2].InstancePath != "/billing/city" {
+		t.Errorf("expected the JSON Pointer to be kept, got %q", verrs[2].InstancePath)
+	}
+
+	if order.Email != "keep@example.com" || order.Status != "" {
+		t.Errorf("expected v unchanged on failure, got %+v", order)
+	}
+}
+
+func TestDecode_Errors(t *testing.T) {
+	var order DecodeOrder
+
+	if err := parser.Decode([]byte(`{`), &order, schema.DefaultOptions()); !errors.Is(err, parser.ErrInvalidJSON) {
+		t.Errorf("expected ErrInvalidJSON, got %v", err)
+	}
+
+	if err := parser.Decode([]byte(`[]`), &order, schema.DefaultOptions()); !errors.Is(err, parser.ErrNotJSONObject) {
+		t.Errorf("expected ErrNotJSONObject, got %v", err)
+	}
+
+	if err := parser.Decode([]byte(`{}`), order, schema.DefaultOptions()); !errors.Is(err, parser.ErrNotStruct) {
+		t.Errorf("expected ErrNotStruct for a non-pointer, got %v", err)
+	}
+}
+
+func TestDecode_CanonicalizesKeys(t *testing.T) {
+	var order DecodeOrder
+
+	// encoding/json would decode EMAIL into Email, so it must be validated.
+	err := parser.Decode([]byte(`{"EMAIL": "nope"}`), &order, schema.DefaultOptions())
+
+	var verrs parser.ValidationErrors
+	if !errors.As(err, &verrs) || len(verrs) != 1 || verrs[0].Field != "Email" || verrs[0].Keyword != "format" {
+		t.Fatalf("expected an Email format error, got %v", err)
+	}
+
+	data := `{"Email": "a@example.com", "email": "b@example.com", "Lines": [{"SKU": "a"}], "unknown": 1}`
+	if err := parser.Decode([]byte(data), &order, schema.DefaultOptions()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if order.Email != "b@example.com" || len(order.Lines) != 1 || order.Lines[0].SKU != "a" || order.Lines[0].Qty != 1 {
+		t.Errorf("expected the exact key to win and case variants to be decoded, got %+v", order)
+	}
+}
+
+func TestDecode_DropsHiddenFields(t *testing.T) {
+	opts := schema.DefaultOptions()
+	opts.Role = "clerk"
+	opts.RolePermissions = map[string]schema.FieldPermissions{"clerk": {"status": schema.AccessHidden}}
+
+	var order DecodeOrder
+
+	// A hidden field is not validated for the role, so it is not decoded.
+	if err := parser.Decode([]byte(`{"email": "a@example.com", "Status": "lost"}`), &order, opts); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if order.Status != "new" {
+		t.Errorf("expected the submitted status to be dropped for its default, got %q", order.Status)
+	}
+}
@@ -0,0 +1,77 @@
+package parser
+
+import (
+	"reflect"
+	"sort"
+
+	"github.com/holdemlab/ui-json-schema/schema"
+)
+
+// errKeywordRequired is the error keyword whose message is set on the
+// parent object.
+const errKeywordRequired = "required"
+
+// applyErrorMessages sets the errorMessage keyword (ajv-errors) from the
+// errmsg tag of a struct field. Messages are translated under the derived
+// key "<Type>.<field>.error.<keyword>", else the message itself used as the
+// key, and their placeholders are filled with the field constraints. The
+// "required" message belongs to the parent object, since ajv reports
+// missing properties there.
+func applyErrorMessages(prop, parent *schema.JSONSchema, owner reflect.Type, name string, tags schema.FieldTags, opts *schema.Options) {
+	if len(tags.ErrorMessages) == 0 {
+		return
+	}
+
+	params := tags.Params()
+
+	for _, keyword := range sortedErrorKeywords(tags.ErrorMessages) {
+		msg := errorMessage(owner, name, keyword, tags.ErrorMessages[keyword], params, opts)
+
+		if keyword == errKeywordRequired {
+			required, _ := ensureErrorMessage(parent)[errKeywordRequired].(map[string]any)
+			if required == nil {
+				required = make(map[string]any)
+				parent.ErrorMessage[errKeywordRequired] = required
+			}
+
+			required[name] = msg
+
+			continue
+		}
+
+		ensureErrorMessage(prop)[keyword] = msg
+	}
+}
+
+// errorMessage returns the localized, formatted message for a keyword.
+func errorMessage(owner reflect.Type, name, keyword, msg string, params map[string]any, opts *schema.Options) string {
+	key := derivedKey(owner, name, keyError, opts) + "." + keyword
+
+	if text, ok := resolveTextParams(opts, msg, params, key, msg); ok {
+		return text
+	}
+
+	return schema.FormatMessage(msg, params)
+}
+
+// ensureErrorMessage initializes the errorMessage map of s.
+func ensureErrorMessage(s *schema.JSONSchema) map[string]any {
+	if s.ErrorMessage == nil {
+		s.ErrorMessage = make(map[string]any)
+	}
+
+	return s.ErrorMessage
+}
+
+// sortedErrorKeywords returns the keywords of messages in a stable order,
+// so translation lookups and key extraction are deterministic.
+func sortedErrorKeywords(messages map[string]string) []string {
+	keywords := make([]string, 0, len(messages))
+	for k := range messages {
+		keywords = append(keywords, k)
+	}
+
+	sort.Strings(keywords)
+
+	return keywords
+}
@@ -0,0 +1,90 @@
+package parser_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/holdemlab/ui-json-schema/parser"
+	"github.com/holdemlab/ui-json-schema/schema"
+)
+
+type ErrmsgAccount struct {
+	Login string `json:"login" required:"true" pattern:"^[a-z]+$" minLength:"3" errmsg:"pattern=Only lowercase letters;minLength=At least {min} characters;required=Login is needed"`
+	Email string `json:"email" format:"email" errmsg:"Enter a valid email"`
+	Plain string `json:"plain"`
+}
+
+func TestErrorMessages(t *testing.T) {
+	s, err := parser.GenerateJSONSchema(ErrmsgAccount{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	login := s.Properties["login"].ErrorMessage
+	expected := map[string]any{
+		"pattern":   "Only lowercase letters",
+		"minLength": "At least 3 characters",
+	}
+
+	if !reflect.DeepEqual(login, expected) {
+		t.Errorf("unexpected login messages %v", login)
+	}
+
+	if got := s.Properties["email"].ErrorMessage["_"]; got != "Enter a valid email" {
+		t.Errorf("expected catch-all message, got %v", got)
+	}
+
+	if s.Properties["plain"].ErrorMessage != nil {
+		t.Errorf("expected no errorMessage, got %v", s.Properties["plain"].ErrorMessage)
+	}
+
+	data, err := json.Marshal(s.ErrorMessage)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+
+	if string(data) != `{"required":{"login":"Login is needed"}}` {
+		t.Errorf("expected required message on the parent object, got %s", data)
+	}
+}
+
+func TestErrorMessages_Localized(t *testing.T) {
+	opts := schema.DefaultOptions()
+	opts.Translator = schema.NewMapTranslator(map[string]map[string]string{
+		"uk": {
+			"ErrmsgAccount.login.error.pattern": "Лише малі літери",
+			"At least {min} characters":         "Щонайменше {min} символи",
+			"At least {min} characters#many":    "Щонайменше {min} символів",
+			"Login is needed":                   "Вкажіть логін",
+		},
+	})
+	opts.Locale = "uk"
+
+	s, err := parser.GenerateJSONSchemaWithOptions(ErrmsgAccount{}, opts)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	login := s.Properties["login"].ErrorMessage
+	if login["pattern"] != "Лише малі літери" || login["minLength"] != "Щонайменше 3 символи" {
+		t.Errorf("unexpected localized messages %v", login)
+	}
+
+	if got := s.ErrorMessage["required"].(map[string]any)["login"]; got != "Вкажіть логін" {
+		t.Errorf("expected localized required message, got %v", got)
+	}
+
+	if got := s.Properties["email"].ErrorMessage["_"]; got != "Enter a valid email" {
+		t.Errorf("expected untranslated message kept, got %v", got)
+	}
+
+	messages, err := parser.ExtractMessages(ErrmsgAccount{}, schema.DefaultOptions())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if catalogue := parser.Catalogue(messages); catalogue["ErrmsgAccount.login.error.required"] != "Login is needed" {
+		t.Errorf("expected error messages in the catalogue, got %v", catalogue)
+	}
+}
@@ -2465,3 +2465,46 @@ func TestGenerateUISchema_GroupInCategory_JSON(t *testing.T) {
 		t.Errorf("expected 2 elements in Group, got %d", len(groupElems))
 	}
 }
+
+// --- Options pass-through ---
+
+type PassThroughItem struct {
+	Title string `json:"title"`
+}
+
+type PassThroughForm struct {
+	Name    string            `json:"name" form:"placeholder=Your name;trim;opt.autoFocus=true"`
+	Active  bool              `json:"active" form:"toggle=false"`
+	Created string            `json:"created" format:"date" form:"opt.format=date-time"`
+	Items   []PassThroughItem `json:"items" form:"showSortButtons;elementLabelProp=title"`
+}
+
+func TestGenerateUISchema_PassThroughOptions(t *testing.T) {
+	ui, err := parser.GenerateUISchema(PassThroughForm{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	name := ui.Elements[0].Options
+	if name["placeholder"] != "Your name" || name["trim"] != true || name["autoFocus"] != true {
+		t.Errorf("unexpected name options: %v", name)
+	}
+
+	// Explicit options win over inferred widgets.
+	if ui.Elements[1].Options["toggle"] != false {
+		t.Errorf("expected explicit toggle=false, got %v", ui.Elements[1].Options)
+	}
+
+	if ui.Elements[2].Options["format"] != "date-time" {
+		t.Errorf("expected explicit format date-time, got %v", ui.Elements[2].Options)
+	}
+
+	items := ui.Elements[3].Options
+	if items["showSortButtons"] != true || items["elementLabelProp"] != "title" {
+		t.Errorf("unexpected array options: %v", items)
+	}
+
+	if _, ok := items["detail"]; !ok {
+		t.Error("expected detail to be kept alongside pass-through options")
+	}
+}
@@ -0,0 +1,132 @@
+package parser
+
+import (
+	"sort"
+
+	"github.com/holdemlab/ui-json-schema/schema"
+)
+
+// categoryHintKeys lists the internal option hints consumed by
+// buildCategorization (see applyGroupCategoryOptions).
+var categoryHintKeys = []string{"category", "categoryRuleEffect", "categoryRuleExpr", "categoryI18n"}
+
+// internalOptionKeys lists every option hint passed between generation
+// passes. None of them may appear in the final UI Schema.
+var internalOptionKeys = []string{
+	"category", "categoryRuleEffect", "categoryRuleExpr", "categoryI18n",
+	"layout", "layoutGroup",
+}
+
+// builtElement pairs a generated UI Schema element with the form tag
+// options of the field it was built from.
+type builtElement struct {
+	el       *schema.UISchemaElement
+	formOpts schema.FormOptions
+}
+
+// order returns the form tag "order=" value, or 0 when unset.
+func (b builtElement) order() int {
+	if b.formOpts.Order == nil {
+		return 0
+	}
+
+	return *b.formOpts.Order
+}
+
+// sortByOrder stably sorts elements by their form tag "order=" value,
+// so unordered fields keep their declaration order.
+func sortByOrder(built []builtElement) {
+	sort.SliceStable(built, func(i, j int) bool {
+		return built[i].order() < built[j].order()
+	})
+}
+
+// wrapNamedGroups wraps elements sharing a form tag "group=" name into a
+// single Group placed at the position of the first member. Category hints
+// of the members are lifted onto the Group so it lands in the right
+// Category as a whole.
+func wrapNamedGroups(built []builtElement, opts *schema.Options) []*schema.UISchemaElement {
+	result := make([]*schema.UISchemaElement, 0, len(built))
+	groups := make(map[string]*schema.UISchemaElement)
+	groupOrder := make([]*schema.UISchemaElement, 0)
+
+	for _, b := range built {
+		name := b.formOpts.Group
+		if name == "" {
+			result = append(result, b.el)
+			continue
+		}
+
+		group, exists := groups[name]
+		if !exists {
+			group = schema.NewGroup(translateLabel(name, "", opts))
+			groups[name] = group
+			groupOrder = append(groupOrder, group)
+			result = append(result, group)
+		}
+
+		group.Elements = append(group.Elements, b.el)
+	}
+
+	for _, group := range groupOrder {
+		liftCategoryHints(group)
+		group.Elements = groupHorizontalElements(group.Elements)
+	}
+
+	return result
+}
+
+// liftCategoryHints moves category hints from the children of a layout
+// onto the layout itself. The first child carrying a hint wins; the hints
+// are removed from all children.
+func liftCategoryHints(layout *schema.UISchemaElement) {
+	for _, el := range layout.Elements {
+		if el.Options == nil {
+			continue
+		}
+
+		for _, key := range categoryHintKeys {
+			v, ok := el.Options[key]
+			if !ok {
+				continue
+			}
+
+			ensureOptions(layout)
+
+			if _, exists := layout.Options[key]; !exists {
+				layout.Options[key] = v
+			}
+
+			delete(el.Options, key)
+		}
+
+		if len(el.Options) == 0 {
+			el.Options = nil
+		}
+	}
+}
+
+// stripInternalOptions removes internal generation hints from an element
+// and all of its descendants, including array detail layouts. Hints are
+// normally consumed by the layout passes; this guarantees none leak into
+// the final UI Schema (e.g. a category rule on a field without category,
+// or hints on a field placed by a template).
+func stripInternalOptions(el *schema.UISchemaElement) {
+	if el.Options != nil {
+		for _, key := range internalOptionKeys {
+			delete(el.Options, key)
+		}
+
+		if detail, ok := el.Options["detail"].(*schema.UISchemaElement); ok {
+			stripInternalOptions(detail)
+		}
+
+		if len(el.Options) == 0 {
+			el.Options = nil
+		}
+	}
+
+	for _, child := range el.Elements {
+		stripInternalOptions(child)
+	}
+}
@@ -0,0 +1,155 @@
+package parser_test
+
+import (
+	"testing"
+
+	"github.com/holdemlab/ui-json-schema/parser"
+	"github.com/holdemlab/ui-json-schema/schema"
+)
+
+type OrderedForm struct {
+	Notes string `json:"notes" form:"order=10"`
+	Email string `json:"email"`
+	Name  string `json:"name" form:"order=-1"`
+	Phone string `json:"phone"`
+}
+
+func TestGenerateUISchema_Order(t *testing.T) {
+	ui, err := parser.GenerateUISchema(OrderedForm{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []string{"#/properties/name", "#/properties/email", "#/properties/phone", "#/properties/notes"}
+	if len(ui.Elements) != len(want) {
+		t.Fatalf("expected %d elements, got %d", len(want), len(ui.Elements))
+	}
+
+	for i, scope := range want {
+		if ui.Elements[i].Scope != scope {
+			t.Errorf("element %d: expected %s, got %s", i, scope, ui.Elements[i].Scope)
+		}
+	}
+}
+
+type OrderedNested struct {
+	Street string `json:"street" form:"order=2"`
+	City   string `json:"city" form:"order=1"`
+}
+
+type OrderedParent struct {
+	Address OrderedNested `json:"address"`
+	Name    string        `json:"name" form:"order=-1"`
+}
+
+func TestGenerateUISchema_OrderNested(t *testing.T) {
+	ui, err := parser.GenerateUISchema(OrderedParent{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if ui.Elements[0].Scope != "#/properties/name" {
+		t.Errorf("expected name first, got %+v", ui.Elements[0])
+	}
+
+	group := ui.Elements[1]
+	if group.Type != "Group" {
+		t.Fatalf("expected Group, got %s", group.Type)
+	}
+
+	if group.Elements[0].Scope != "#/properties/address/properties/city" {
+		t.Errorf("expected city first inside group, got %s", group.Elements[0].Scope)
+	}
+}
+
+type NamedGroupForm struct {
+	Name    string `json:"name"`
+	Card    string `json:"card" form:"group=Billing"`
+	Email   string `json:"email"`
+	Expires string `json:"expires" form:"group=Billing"`
+}
+
+func TestGenerateUISchema_NamedGroup(t *testing.T) {
+	ui, err := parser.GenerateUISchema(NamedGroupForm{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(ui.Elements) != 3 {
+		t.Fatalf("expected 3 elements, got %d", len(ui.Elements))
+	}
+
+	group := ui.Elements[1]
+	if group.Type != "Group" || group.Label != "Billing" {
+		t.Fatalf("expected Billing group at position 1, got %+v", group)
+	}
+
+	if len(group.Elements) != 2 ||
+		group.Elements[0].Scope != "#/properties/card" ||
+		group.Elements[1].Scope != "#/properties/expires" {
+		t.Errorf("unexpected group members: %+v", group.Elements)
+	}
+
+	if ui.Elements[2].Scope != "#/properties/email" {
+		t.Errorf("expected email after group, got %s", ui.Elements[2].Scope)
+	}
+}
+
+type GroupInCategoryForm struct {
+	Login   string `json:"login" form:"category=Account"`
+	Card    string `json:"card" form:"category=Account;group=Billing;layout=horizontal"`
+	Expires string `json:"expires" form:"category=Account;group=Billing;layout=horizontal"`
+	Theme   string `json:"theme" form:"category=Preferences"`
+}
+
+func TestGenerateUISchema_NamedGroupInCategory(t *testing.T) {
+	ui, err := parser.GenerateUISchema(GroupInCategoryForm{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if ui.Type != "Categorization" || len(ui.Elements) != 2 {
+		t.Fatalf("expected Categorization with 2 categories, got %s with %d", ui.Type, len(ui.Elements))
+	}
+
+	account := ui.Elements[0]
+	if account.Label != "Account" || len(account.Elements) != 2 {
+		t.Fatalf("unexpected Account category: %+v", account)
+	}
+
+	group := account.Elements[1]
+	if group.Type != "Group" || group.Label != "Billing" {
+		t.Fatalf("expected Billing group inside Account, got %+v", group)
+	}
+
+	if group.Options != nil {
+		t.Errorf("expected category hint to be consumed, got %v", group.Options)
+	}
+
+	if len(group.Elements) != 1 || group.Elements[0].Type != "HorizontalLayout" {
+		t.Fatalf("expected a HorizontalLayout inside the group, got %+v", group.Elements)
+	}
+
+	for _, el := range group.Elements[0].Elements {
+		if el.Options != nil {
+			t.Errorf("expected internal hints removed from %s, got %v", el.Scope, el.Options)
+		}
+	}
+}
+
+func TestGenerateUISchema_NamedGroupTranslated(t *testing.T) {
+	opts := schema.DefaultOptions()
+	opts.Translator = schema.NewMapTranslator(map[string]map[string]string{
+		"uk": {"Billing": "Оплата"},
+	})
+	opts.Locale = "uk"
+
+	ui, err := parser.GenerateUISchemaWithOptions(NamedGroupForm{}, opts)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if ui.Elements[1].Label != "Оплата" {
+		t.Errorf("expected translated group label, got %q", ui.Elements[1].Label)
+	}
+}
@@ -0,0 +1,234 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/holdemlab/ui-json-schema/schema"
+)
+
+// ErrNoLocales is returned by GenerateLocalized when the Translator cannot
+// list its locales.
+var ErrNoLocales = errors.New("translator does not list its locales")
+
+// Suffixes of the derived i18n keys "<Type>.<field>.<suffix>".
+const (
+	keyLabel       = "label"
+	keyDescription = "description"
+	keyEnum        = "enum"
+	keyTitle       = "title"
+	keyError       = "error"
+)
+
+// SchemaBundle holds the JSON Schema and UI Schema generated for one locale.
+type SchemaBundle struct {
+	JSONSchema *schema.JSONSchema      `json:"schema"`
+	UISchema   *schema.UISchemaElement `json:"uischema"`
+}
+
+// GenerateLocalized generates the JSON Schema and UI Schema of v for every
+// locale listed by opts.Translator, which must implement schema.LocaleLister.
+// opts.Locale is ignored; the result is keyed by locale.
+func GenerateLocalized(v any, opts schema.Options) (map[string]SchemaBundle, error) {
+	lister, ok := opts.Translator.(schema.LocaleLister)
+	if !ok {
+		return nil, fmt.Errorf("%w: %T", ErrNoLocales, opts.Translator)
+	}
+
+	bundles := make(map[string]SchemaBundle)
+
+	for _, locale := range lister.Locales() {
+		opts.Locale = locale
+
+		js, err := GenerateJSONSchemaWithOptions(v, opts)
+		if err != nil {
+			return nil, err
+		}
+
+		ui, err := GenerateUISchemaWithOptions(v, opts)
+		if err != nil {
+			return nil, err
+		}
+
+		bundles[locale] = SchemaBundle{JSONSchema: js, UISchema: ui}
+	}
+
+	return bundles, nil
+}
+
+// canTranslate reports whether opts has a Translator and a Locale.
+func canTranslate(opts *schema.Options) bool {
+	return opts != nil && opts.Translator != nil && opts.Locale != ""
+}
+
+// lookupTranslation translates key and reports whether a translation
+// exists. Translators return the key unchanged when it is missing.
+func lookupTranslation(key string, opts *schema.Options) (string, bool) {
+	if !canTranslate(opts) || key == "" {
+		return "", false
+	}
+
+	text := opts.Translator.Translate(key, opts.Locale)
+
+	return text, text != key
+}
+
+// resolveText returns the translation of the first of keys that has one.
+// source is the untranslated text shown when none has; a key collector
+// records the keys and source instead (see ExtractMessages).
+func resolveText(opts *schema.Options, source string, keys ...string) (string, bool) {
+	return resolveTextParams(opts, source, nil, keys...)
+}
+
+// resolveTextParams is resolveText formatting the translation with params
+// (see schema.ParamTranslator).
+func resolveTextParams(opts *schema.Options, source string, params map[string]any, keys ...string) (string, bool) {
+	if !canTranslate(opts) {
+		return "", false
+	}
+
+	if c, ok := opts.Translator.(*keyCollector); ok {
+		c.add(source, keys, false)
+		return "", false
+	}
+
+	for _, key := range keys {
+		text, ok := lookupTranslation(key, opts)
+		if !ok {
+			continue
+		}
+
+		if params != nil {
+			text = schema.TranslateParams(opts.Translator, key, opts.Locale, params)
+		}
+
+		return text, true
+	}
+
+	return "", false
+}
+
+// resolveOptionalText is resolveText for a text whose source is a complete
+// fallback; a key collector records it as an Optional message.
+func resolveOptionalText(opts *schema.Options, source string, keys ...string) (string, bool) {
+	if !canTranslate(opts) {
+		return "", false
+	}
+
+	if c, ok := opts.Translator.(*keyCollector); ok {
+		c.add(source, keys, true)
+		return "", false
+	}
+
+	return resolveText(opts, source, keys...)
+}
+
+// derivedKey builds the conventional i18n key "<Type>.<field>.<suffix>"
+// for a field of the owner struct type.
+func derivedKey(owner reflect.Type, field, suffix string, opts *schema.Options) string {
+	return typeName(owner, opts) + "." + field + "." + suffix
+}
+
+// fieldLabel returns the localized label of a struct field. An explicit
+// i18n tag key wins; otherwise the derived key "<Type>.<field>.label" is
+// tried before the label itself is used as the key (see translateLabel).
+func fieldLabel(owner reflect.Type, name, label, i18nKey string, opts *schema.Options) string {
+	if !canTranslate(opts) {
+		return translateLabel(label, i18nKey, opts)
+	}
+
+	keys := []string{i18nKey}
+	if i18nKey == "" {
+		keys = []string{label}
+		if owner != nil {
+			keys = []string{derivedKey(owner, name, keyLabel, opts), label}
+		}
+	}
+
+	source := label
+	if source == "" {
+		source = name
+	}
+
+	if text, ok := resolveText(opts, source, keys...); ok {
+		return text
+	}
+
+	if i18nKey != "" {
+		return i18nKey
+	}
+
+	return label
+}
+
+// fieldDescription returns the localized description of a struct field:
+// the derived key "<Type>.<field>.description", else the description tag
+// used as the key. Placeholders are filled from params, the constraints of
+// the field, whether or not a translation exists.
+func fieldDescription(owner reflect.Type, name, description string, params map[string]any, opts *schema.Options) string {
+	if text, ok := resolveTextParams(opts, description, params, derivedKey(owner, name, keyDescription, opts), description); ok {
+		return text
+	}
+
+	return schema.FormatMessage(description, params)
+}
+
+// localizeProperty applies translations to a struct field property: the
+// description, a title from the localized label and enum display names.
+// Without a Translator the property is left unchanged.
+func localizeProperty(prop *schema.JSONSchema, owner reflect.Type, name string, tags schema.FieldTags, opts *schema.Options) {
+	if !canTranslate(opts) {
+		return
+	}
+
+	prop.Description = fieldDescription(owner, name, tags.Description, tags.Params(), opts)
+
+	label := schema.ParseFormTag(tags.Form).Label
+	if title := fieldLabel(owner, name, label, tags.I18nKey, opts); title != "" {
+		prop.Title = title
+	}
+
+	localizeEnum(prop, owner, name, opts)
+}
+
+// localizeEnum replaces an enum with oneOf const/title pairs when any value
+// has a translation under "<Type>.<field>.enum.<value>", so renderers show
+// display names while validating the same values.
+func localizeEnum(prop *schema.JSONSchema, owner reflect.Type, name string, opts *schema.Options) {
+	if len(prop.Enum) == 0 {
+		return
+	}
+
+	options := make([]*schema.JSONSchema, 0, len(prop.Enum))
+	translated := false
+
+	for _, value := range prop.Enum {
+		title := fmt.Sprint(value)
+
+		key := derivedKey(owner, name, keyEnum, opts) + "." + title
+		if text, ok := resolveOptionalText(opts, title, key); ok {
+			title = text
+			translated = true
+		}
+
+		options = append(options, &schema.JSONSchema{Const: value, Title: title})
+	}
+
+	if translated {
+		prop.OneOf = options
+		prop.Enum = nil
+	}
+}
+
+// typeTitle returns the localized title of a struct type: the key
+// "<Type>.title", else the type name itself.
+func typeTitle(t reflect.Type, opts *schema.Options) string {
+	name := typeName(t, opts)
+
+	if text, ok := resolveText(opts, name, name+"."+keyTitle); ok {
+		return text
+	}
+
+	return name
+}
@@ -0,0 +1,238 @@
+package parser_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/holdemlab/ui-json-schema/parser"
+	"github.com/holdemlab/ui-json-schema/schema"
+)
+
+type LocalizedAddress struct {
+	City string `json:"city"`
+}
+
+type LocalizedLine struct {
+	SKU string `json:"sku"`
+}
+
+type LocalizedOrder struct {
+	Name    string           `json:"name" description:"Customer name"`
+	Status  string           `json:"status" enum:"new,paid"`
+	Note    string           `json:"note" i18n:"order.note" description:"Free text"`
+	Address LocalizedAddress `json:"address"`
+	Lines   []LocalizedLine  `json:"lines"`
+}
+
+func localizedTranslator() *schema.MapTranslator {
+	return schema.NewMapTranslator(map[string]map[string]string{
+		"uk": {
+			"LocalizedOrder.title":              "Замовлення",
+			"LocalizedOrder.name.label":         "Ім'я",
+			"LocalizedOrder.name.description":   "Ім'я клієнта",
+			"LocalizedOrder.status.enum.new":    "Нове",
+			"LocalizedOrder.note.label":         "Ignored: explicit key wins",
+			"order.note":                        "Примітка",
+			"Free text":                         "Довільний текст",
+			"LocalizedOrder.address.label":      "Адреса",
+			"LocalizedOrder.lines.label":        "Рядки",
+			"LocalizedAddress.city.label":       "Місто",
+			"LocalizedAddress.city.description": "Назва міста",
+			"LocalizedLine.sku.label":           "Артикул",
+		},
+		"en": {
+			"LocalizedOrder.name.label": "Name",
+		},
+	})
+}
+
+func localizedOptions(locale string) schema.Options {
+	opts := schema.DefaultOptions()
+	opts.Translator = localizedTranslator()
+	opts.Locale = locale
+
+	return opts
+}
+
+func TestLocalize_JSONSchema(t *testing.T) {
+	opts := localizedOptions("uk")
+	opts.RootTitle = true
+
+	s, err := parser.GenerateJSONSchemaWithOptions(LocalizedOrder{}, opts)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if s.Title != "Замовлення" {
+		t.Errorf("expected localized root title, got %q", s.Title)
+	}
+
+	name := s.Properties["name"]
+	if name.Title != "Ім'я" || name.Description != "Ім'я клієнта" {
+		t.Errorf("expected derived title and description, got %q / %q", name.Title, name.Description)
+	}
+
+	note := s.Properties["note"]
+	if note.Title != "Примітка" || note.Description != "Довільний текст" {
+		t.Errorf("expected explicit key title and description as key, got %q / %q", note.Title, note.Description)
+	}
+
+	status := s.Properties["status"]
+	if status.Enum != nil || len(status.OneOf) != 2 {
+		t.Fatalf("expected enum replaced by oneOf, got %+v", status)
+	}
+
+	if status.OneOf[0].Const != "new" || status.OneOf[0].Title != "Нове" {
+		t.Errorf("expected translated enum title, got %+v", status.OneOf[0])
+	}
+
+	if status.OneOf[1].Const != "paid" || status.OneOf[1].Title != "paid" {
+		t.Errorf("expected untranslated enum value as title, got %+v", status.OneOf[1])
+	}
+
+	// Nested and array item structs use their own type for derived keys.
+	city := s.Properties["address"].Properties["city"]
+	if city.Title != "Місто" || city.Description != "Назва міста" {
+		t.Errorf("expected nested field localization, got %q / %q", city.Title, city.Description)
+	}
+
+	if sku := s.Properties["lines"].Items.Properties["sku"]; sku.Title != "Артикул" {
+		t.Errorf("expected array item localization, got %q", sku.Title)
+	}
+}
+
+func TestLocalize_NoTranslator(t *testing.T) {
+	s, err := parser.GenerateJSONSchema(LocalizedOrder{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if s.Properties["name"].Title != "" || s.Properties["name"].Description != "Customer name" {
+		t.Errorf("expected untouched property, got %+v", s.Properties["name"])
+	}
+
+	if len(s.Properties["status"].Enum) != 2 || s.Properties["status"].OneOf != nil {
+		t.Errorf("expected plain enum, got %+v", s.Properties["status"])
+	}
+}
+
+func TestLocalize_UISchema(t *testing.T) {
+	ui, err := parser.GenerateUISchemaWithOptions(LocalizedOrder{}, localizedOptions("uk"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	labels := map[string]string{}
+	for _, el := range ui.Elements {
+		labels[el.Scope+el.Type] = el.Label
+	}
+
+	if labels["#/properties/nameControl"] != "Ім'я" {
+		t.Errorf("expected derived control label, got %q", labels["#/properties/nameControl"])
+	}
+
+	if labels["#/properties/noteControl"] != "Примітка" {
+		t.Errorf("expected explicit key label, got %q", labels["#/properties/noteControl"])
+	}
+
+	address := ui.Elements[3]
+	if address.Type != "Group" || address.Label != "Адреса" {
+		t.Errorf("expected translated Group label instead of Go field name, got %q", address.Label)
+	}
+
+	if address.Elements[0].Label != "Місто" {
+		t.Errorf("expected nested control label, got %q", address.Elements[0].Label)
+	}
+
+	lines := ui.Elements[4]
+	if lines.Label != "Рядки" {
+		t.Errorf("expected array control label, got %q", lines.Label)
+	}
+
+	detail, ok := lines.Options["detail"].(*schema.UISchemaElement)
+	if !ok || detail.Elements[0].Label != "Артикул" {
+		t.Errorf("expected array detail label, got %v", lines.Options["detail"])
+	}
+}
+
+func TestLocalize_GroupWithoutTranslation(t *testing.T) {
+	ui, err := parser.GenerateUISchemaWithOptions(LocalizedOrder{}, localizedOptions("en"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if ui.Elements[3].Label != "Address" {
+		t.Errorf("expected Go field name fallback, got %q", ui.Elements[3].Label)
+	}
+}
+
+func TestGenerateLocalized(t *testing.T) {
+	bundles, err := parser.GenerateLocalized(LocalizedOrder{}, localizedOptions(""))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(bundles) != 2 {
+		t.Fatalf("expected bundles for en and uk, got %d", len(bundles))
+	}
+
+	if got := bundles["uk"].JSONSchema.Properties["name"].Title; got != "Ім'я" {
+		t.Errorf("expected uk title, got %q", got)
+	}
+
+	if got := bundles["en"].UISchema.Elements[0].Label; got != "Name" {
+		t.Errorf("expected en label, got %q", got)
+	}
+}
+
+type plainTranslator struct{}
+
+func (plainTranslator) Translate(key, _ string) string { return key }
+
+func TestGenerateLocalized_NoLocales(t *testing.T) {
+	opts := schema.DefaultOptions()
+	opts.Translator = plainTranslator{}
+
+	if _, err := parser.GenerateLocalized(LocalizedOrder{}, opts); !errors.Is(err, parser.ErrNoLocales) {
+		t.Errorf("expected ErrNoLocales, got %v", err)
+	}
+
+	if _, err := parser.GenerateLocalized(LocalizedOrder{}, schema.DefaultOptions()); !errors.Is(err, parser.ErrNoLocales) {
+		t.Errorf("expected ErrNoLocales without translator, got %v", err)
+	}
+}
+
+type LocalizedSignup struct {
+	Login string `json:"login" minLength:"3" description:"At least {min} characters"`
+	Tags  string `json:"tags" maxLength:"1"`
+	Code  string `json:"code" minLength:"5" description:"Code of {minLength} characters"`
+}
+
+func TestLocalize_DescriptionParams(t *testing.T) {
+	opts := schema.DefaultOptions()
+	opts.Translator = schema.NewMapTranslator(map[string]map[string]string{
+		"uk": {
+			"At least {min} characters":            "Щонайменше {min} символів",
+			"LocalizedSignup.tags.description":     "До {max} тегів",
+			"LocalizedSignup.tags.description#one": "Не більше {max} тегу",
+		},
+	})
+	opts.Locale = "uk"
+
+	s, err := parser.GenerateJSONSchemaWithOptions(LocalizedSignup{}, opts)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := s.Properties["login"].Description; got != "Щонайменше 3 символів" {
+		t.Errorf("expected formatted translation, got %q", got)
+	}
+
+	if got := s.Properties["tags"].Description; got != "Не більше 1 тегу" {
+		t.Errorf("expected plural form selected by the constraint, got %q", got)
+	}
+
+	if got := s.Properties["code"].Description; got != "Code of 5 characters" {
+		t.Errorf("expected untranslated description formatted, got %q", got)
+	}
+}
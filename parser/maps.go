@@ -0,0 +1,128 @@
+package parser
+
+import (
+	"encoding"
+	"reflect"
+
+	"github.com/holdemlab/ui-json-schema/schema"
+)
+
+// mapKeyLabel is the default label of the key field in a map editor.
+const mapKeyLabel = "Key"
+
+// MapRenderer is the renderer name set on the Control of a map-of-structs
+// field. Stock JSON Forms renderers cannot edit object keys, so the client
+// registers a renderer under this name that edits entries as key/value
+// pairs: options.key is the key Control, scoped to the map's
+// propertyNames, and options.detail the layout of a value, scoped to
+// additionalProperties. A renderer tag or Options.Renderers entry replaces
+// the name.
+const MapRenderer = "map"
+
+// mapKeyScope is the scope of the key Control, relative to the map schema.
+const mapKeyScope = "#/propertyNames"
+
+// Key patterns matching how encoding/json writes integer map keys.
+const (
+	intKeyPattern  = "^-?[0-9]+$"
+	uintKeyPattern = "^[0-9]+$"
+)
+
+// textMarshalerType is cached to avoid repeated reflect.TypeOf calls.
+var textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+
+// mapKeySchema returns the propertyNames schema for a map key type,
+// following the key encoding of encoding/json: string keys are used as is
+// (nil schema), TextMarshaler keys as their text and integer keys as
+// decimal strings. ok is false for key types encoding/json rejects.
+func mapKeySchema(k reflect.Type) (*schema.JSONSchema, bool) {
+	if k.Kind() == reflect.String {
+		return nil, true
+	}
+
+	if k.Implements(textMarshalerType) {
+		if k == timeType {
+			return &schema.JSONSchema{Format: "date-time"}, true
+		}
+
+		return &schema.JSONSchema{Type: "string"}, true
+	}
+
+	switch k.Kind() { //nolint:exhaustive // only integer keys are encodable
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return &schema.JSONSchema{Pattern: intKeyPattern}, true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return &schema.JSONSchema{Pattern: uintKeyPattern}, true
+	default:
+		return nil, false
+	}
+}
+
+// applyKeyTags applies the keyPattern and keyFormat struct tags to the
+// propertyNames of a map property.
+func applyKeyTags(prop *schema.JSONSchema, tags schema.FieldTags) {
+	if prop.Type != "object" || prop.AdditionalProperties == nil || (tags.KeyPattern == "" && tags.KeyFormat == "") {
+		return
+	}
+
+	if prop.PropertyNames == nil {
+		prop.PropertyNames = &schema.JSONSchema{}
+	}
+
+	if tags.KeyPattern != "" {
+		prop.PropertyNames.Pattern = tags.KeyPattern
+	}
+
+	if tags.KeyFormat != "" {
+		prop.PropertyNames.Format = tags.KeyFormat
+	}
+}
+
+// mapOfStructsElemType checks if the type is a JSON-encodable map whose
+// value type (after unwrapping pointers) is a struct (excluding time.Time).
+// Returns the underlying struct type and true, or nil and false.
+func mapOfStructsElemType(t reflect.Type) (reflect.Type, bool) {
+	if t.Kind() != reflect.Map {
+		return nil, false
+	}
+
+	if _, ok := mapKeySchema(t.Key()); !ok {
+		return nil, false
+	}
+
+	elem := t.Elem()
+	if elem.Kind() == reflect.Ptr {
+		elem = elem.Elem()
+	}
+
+	if elem.Kind() == reflect.Struct && elem != timeType {
+		return elem, true
+	}
+
+	return nil, false
+}
+
+// buildMapControl creates a key/value editor Control for a map-of-structs
+// field, rendered by MapRenderer: options.key is the key Control,
+// options.keyLabel its label and options.detail the UI Schema of the value
+// struct.
+func buildMapControl(owner reflect.Type, scope, name string, formOpts schema.FormOptions, tags schema.FieldTags,
+	opts *schema.Options, elemType reflect.Type) *schema.UISchemaElement {
+	control := buildControl(owner, scope, name, formOpts, tags, opts)
+
+	if detail := buildArrayDetail(elemType, opts); detail != nil {
+		ensureOptions(control)
+		control.Options["detail"] = detail
+	}
+
+	setDefaultOption(control, "renderer", MapRenderer)
+	setDefaultOption(control, "keyLabel", translateLabel(mapKeyLabel, "", opts))
+
+	key := schema.NewControl(mapKeyScope)
+	key.Label, _ = control.Options["keyLabel"].(string)
+	setDefaultOption(control, "key", key)
+
+	applyLayoutOptions(control, formOpts)
+
+	return control
+}
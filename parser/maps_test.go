@@ -0,0 +1,168 @@
+package parser_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/holdemlab/ui-json-schema/parser"
+	"github.com/holdemlab/ui-json-schema/schema"
+)
+
+type RegionCode string
+
+type MapEndpoint struct {
+	URL     string `json:"url" required:"true"`
+	Timeout int    `json:"timeout"`
+}
+
+type MapConfig struct {
+	Labels    map[string]string         `json:"labels"`
+	ByID      map[int]string            `json:"by_id"`
+	ByPort    map[uint16]MapEndpoint    `json:"by_port"`
+	ByDay     map[time.Time]int         `json:"by_day"`
+	Regions   map[RegionCode]string     `json:"regions"`
+	Rates     map[string]float64        `json:"rates" keyPattern:"^[A-Z]{3}$"`
+	Endpoints map[string]*MapEndpoint   `json:"endpoints" form:"label=Endpoints;opt.keyLabel=Name"`
+	Broken    map[float64]string        `json:"broken"`
+	Nested    map[string]map[int]string `json:"nested"`
+}
+
+func TestMapKeys_JSONSchema(t *testing.T) {
+	s, err := parser.GenerateJSONSchema(MapConfig{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		field   string
+		pattern string
+		format  string
+		none    bool
+	}{
+		{field: "labels", none: true},
+		{field: "regions", none: true},
+		{field: "by_id", pattern: "^-?[0-9]+$"},
+		{field: "by_port", pattern: "^[0-9]+$"},
+		{field: "by_day", format: "date-time"},
+		{field: "rates", pattern: "^[A-Z]{3}$"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.field, func(t *testing.T) {
+			prop := s.Properties[tt.field]
+			if prop.AdditionalProperties == nil {
+				t.Fatalf("expected additionalProperties on %s", tt.field)
+			}
+
+			if tt.none {
+				if prop.PropertyNames != nil {
+					t.Errorf("expected no propertyNames, got %+v", prop.PropertyNames)
+				}
+
+				return
+			}
+
+			if prop.PropertyNames == nil {
+				t.Fatal("expected propertyNames")
+			}
+
+			if prop.PropertyNames.Pattern != tt.pattern || prop.PropertyNames.Format != tt.format {
+				t.Errorf("expected pattern %q format %q, got %+v", tt.pattern, tt.format, prop.PropertyNames)
+			}
+		})
+	}
+
+	// Keys encoding/json cannot write keep the bare object.
+	if broken := s.Properties["broken"]; broken.AdditionalProperties != nil || broken.PropertyNames != nil {
+		t.Errorf("expected bare object for float keys, got %+v", broken)
+	}
+
+	nested := s.Properties["nested"].AdditionalProperties
+	if nested.PropertyNames == nil || nested.PropertyNames.Pattern != "^-?[0-9]+$" {
+		t.Errorf("expected integer keys on nested map, got %+v", nested)
+	}
+
+	if value := s.Properties["by_port"].AdditionalProperties; value.Type != "object" || value.Properties["url"] == nil {
+		t.Errorf("expected struct values, got %+v", value)
+	}
+}
+
+func TestMapOfStructs_UISchema(t *testing.T) {
+	ui, err := parser.GenerateUISchema(MapConfig{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	byScope := make(map[string]*schema.UISchemaElement)
+	for _, el := range ui.Elements {
+		byScope[el.Scope] = el
+	}
+
+	labels := byScope["#/properties/labels"]
+	if labels == nil || labels.Options != nil {
+		t.Errorf("expected plain Control for map of strings, got %+v", labels)
+	}
+
+	byPort := byScope["#/properties/by_port"]
+	if byPort == nil || byPort.Options["keyLabel"] != "Key" {
+		t.Fatalf("expected key/value editor for map of structs, got %+v", byPort)
+	}
+
+	detail, ok := byPort.Options["detail"].(*schema.UISchemaElement)
+	if !ok || len(detail.Elements) != 2 || detail.Elements[0].Scope != "#/properties/url" {
+		t.Errorf("expected value detail with url and timeout, got %v", byPort.Options["detail"])
+	}
+
+	if byPort.Options["renderer"] != parser.MapRenderer {
+		t.Errorf("expected the %q renderer, got %v", parser.MapRenderer, byPort.Options["renderer"])
+	}
+
+	key, ok := byPort.Options["key"].(*schema.UISchemaElement)
+	if !ok || key.Type != "Control" || key.Scope != "#/propertyNames" || key.Label != "Key" {
+		t.Errorf("expected a key Control scoped to propertyNames, got %+v", byPort.Options["key"])
+	}
+
+	endpoints := byScope["#/properties/endpoints"]
+	if endpoints == nil || endpoints.Label != "Endpoints" || endpoints.Options["keyLabel"] != "Name" {
+		t.Errorf("expected custom key label for pointer values, got %+v", endpoints)
+	}
+
+	if key, ok := endpoints.Options["key"].(*schema.UISchemaElement); !ok || key.Label != "Name" {
+		t.Errorf("expected the key Control to use the custom label, got %+v", endpoints.Options["key"])
+	}
+}
+
+func TestMapOfStructs_RendererOverride(t *testing.T) {
+	opts := schema.DefaultOptions()
+	opts.Renderers = map[string]string{"#/properties/by_port": "port-table"}
+
+	ui, err := parser.GenerateUISchemaWithOptions(MapConfig{}, opts)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, el := range ui.Elements {
+		if el.Scope == "#/properties/by_port" && el.Options["renderer"] != "port-table" {
+			t.Errorf("expected the configured renderer, got %v", el.Options["renderer"])
+		}
+	}
+}
+
+func TestMapOfStructs_TranslatedKeyLabel(t *testing.T) {
+	opts := schema.DefaultOptions()
+	opts.Locale = "uk"
+	opts.Translator = schema.NewMapTranslator(map[string]map[string]string{
+		"uk": {"Key": "Ключ"},
+	})
+
+	ui, err := parser.GenerateUISchemaWithOptions(MapConfig{}, opts)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, el := range ui.Elements {
+		if el.Scope == "#/properties/by_port" && el.Options["keyLabel"] != "Ключ" {
+			t.Errorf("expected translated key label, got %v", el.Options["keyLabel"])
+		}
+	}
+}
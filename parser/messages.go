@@ -0,0 +1,236 @@
+package parser
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/holdemlab/ui-json-schema/schema"
+)
+
+// collectorLocale is the locale set while collecting keys; the collector
+// ignores it, but generation only translates when a locale is set.
+const collectorLocale = "und"
+
+// Message is a translatable text used by the generated schemas.
+type Message struct {
+	// Key is the preferred i18n key: the explicit i18n tag or the derived
+	// key (e.g. "Order.name.label").
+	Key string
+	// Source is the untranslated text (label, description, enum value).
+	Source string
+	// Keys lists every key the generator tries, Key first; a translation
+	// under any of them satisfies the message.
+	Keys []string
+	// Optional marks texts whose source is a complete fallback, such as
+	// enum values shown as-is until one is translated. MissingTranslations
+	// and CheckTranslations do not report them.
+	Optional bool
+}
+
+// TestReporter is the subset of testing.TB used by CheckTranslations.
+type TestReporter interface {
+	Helper()
+	Errorf(format string, args ...any)
+}
+
+// keyCollector is a Translator that records the messages looked up during
+// generation and translates nothing.
+type keyCollector struct {
+	messages map[string]*Message
+}
+
+// Translate returns the key unchanged.
+func (c *keyCollector) Translate(key, _ string) string { return key }
+
+// add records a message. Texts without source or keys are optional (e.g.
+// a derived description of a field without a description tag) and skipped.
+// A message recorded both as optional and required is required.
+func (c *keyCollector) add(source string, keys []string, optional bool) {
+	var kept []string
+
+	for _, k := range keys {
+		if k != "" {
+			kept = append(kept, k)
+		}
+	}
+
+	if source == "" || len(kept) == 0 {
+		return
+	}
+
+	if m, ok := c.messages[kept[0]]; ok {
+		m.Optional = m.Optional && optional
+
+		for _, k := range kept[1:] {
+			if !containsString(m.Keys, k) {
+				m.Keys = append(m.Keys, k)
+			}
+		}
+
+		return
+	}
+
+	c.messages[kept[0]] = &Message{Key: kept[0], Source: source, Keys: kept, Optional: optional}
+}
+
+// containsString reports whether names contains name.
+func containsString(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ExtractMessages returns every translatable message the JSON Schema and
+// UI Schema of v use with opts — field labels, descriptions, enum values,
+// category, group and variant labels — sorted by key. opts.Translator and
+// opts.Locale are ignored.
+func ExtractMessages(v any, opts schema.Options) ([]Message, error) {
+	c := &keyCollector{messages: make(map[string]*Message)}
+	opts.Translator = c
+	opts.Locale = collectorLocale
+
+	if _, err := GenerateJSONSchemaWithOptions(v, opts); err != nil {
+		return nil, err
+	}
+
+	if _, err := GenerateUISchemaWithOptions(v, opts); err != nil {
+		return nil, err
+	}
+
+	messages := make([]Message, 0, len(c.messages))
+	for _, m := range c.messages {
+		messages = append(messages, *m)
+	}
+
+	sort.Slice(messages, func(i, j int) bool { return messages[i].Key < messages[j].Key })
+
+	return messages, nil
+}
+
+// Catalogue returns a key → source text map of messages, a template for
+// translators that LoadTranslator reads back when saved as JSON.
+func Catalogue(messages []Message) map[string]string {
+	catalogue := make(map[string]string, len(messages))
+	for _, m := range messages {
+		catalogue[m.Key] = m.Source
+	}
+
+	return catalogue
+}
+
+// MissingTranslations reports, per locale, the sorted keys of the required
+// messages of v that opts.Translator cannot translate; Optional messages
+// are not reported. Without locales, those listed
+// by the Translator are checked (see schema.LocaleLister). Complete locales
+// are omitted, so an empty result means everything is translated.
+func MissingTranslations(v any, opts schema.Options, locales ...string) (map[string][]string, error) {
+	locales, err := checkedLocales(opts.Translator, locales)
+	if err != nil {
+		return nil, err
+	}
+
+	messages, err := ExtractMessages(v, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return missingMessages(messages, opts.Translator, locales), nil
+}
+
+// checkedLocales returns locales, or the locales listed by tr when empty.
+func checkedLocales(tr schema.Translator, locales []string) ([]string, error) {
+	if len(locales) > 0 {
+		return locales, nil
+	}
+
+	lister, ok := tr.(schema.LocaleLister)
+	if !ok {
+		return nil, fmt.Errorf("%w: %T", ErrNoLocales, tr)
+	}
+
+	return lister.Locales(), nil
+}
+
+// missingMessages checks the required messages against tr for each locale.
+func missingMessages(messages []Message, tr schema.Translator, locales []string) map[string][]string {
+	missing := make(map[string][]string)
+
+	for _, locale := range locales {
+		for _, m := range messages {
+			if !m.Optional && !isTranslated(m, tr, locale) {
+				missing[locale] = append(missing[locale], m.Key)
+			}
+		}
+	}
+
+	return missing
+}
+
+// isTranslated reports whether any key of m has a translation in locale.
+func isTranslated(m Message, tr schema.Translator, locale string) bool {
+	if tr == nil {
+		return false
+	}
+
+	for _, k := range m.Keys {
+		if tr.Translate(k, locale) != k {
+			return true
+		}
+	}
+
+	return false
+}
+
+// CheckTranslations reports every required message of v that
+// opts.Translator cannot translate in locales (or in all locales it lists)
+// as a test error and returns whether all are translated:
+//
+//	func TestTranslations(t *testing.T) {
+//		parser.CheckTranslations(t, Order{}, opts, "en", "uk")
+//	}
+func CheckTranslations(t TestReporter, v any, opts schema.Options, locales ...string) bool {
+	t.Helper()
+
+	locales, err := checkedLocales(opts.Translator, locales)
+	if err != nil {
+		t.Errorf("checking translations of %T: %v", v, err)
+		return false
+	}
+
+	messages, err := ExtractMessages(v, opts)
+	if err != nil {
+		t.Errorf("checking translations of %T: %v", v, err)
+		return false
+	}
+
+	missing := missingMessages(messages, opts.Translator, locales)
+
+	sources := make(map[string]string, len(messages))
+	for _, m := range messages {
+		sources[m.Key] = m.Source
+	}
+
+	for _, locale := range sortedKeys(missing) {
+		for _, key := range missing[locale] {
+			t.Errorf("%T: missing %q translation for %q (%q)", v, locale, key, sources[key])
+		}
+	}
+
+	return len(missing) == 0
+}
+
+// sortedKeys returns the keys of m in ascending order.
+func sortedKeys(m map[string][]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
@@ -0,0 +1,183 @@
+package parser_test
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/holdemlab/ui-json-schema/parser"
+	"github.com/holdemlab/ui-json-schema/schema"
+)
+
+type MessageProfile struct {
+	Name   string `json:"name" form:"label=Full name;category=Main;i18n=tabs.main"`
+	Bio    string `json:"bio" description:"About you" form:"category=Main"`
+	Status string `json:"status" enum:"on,off" form:"category=Extra"`
+	Note   string `json:"note" i18n:"profile.note" form:"category=Extra"`
+	Secret string `json:"secret" form:"hidden"`
+}
+
+func messageKeys(messages []parser.Message) []string {
+	keys := make([]string, 0, len(messages))
+	for _, m := range messages {
+		keys = append(keys, m.Key)
+	}
+
+	return keys
+}
+
+func TestExtractMessages(t *testing.T) {
+	messages, err := parser.ExtractMessages(MessageProfile{}, schema.DefaultOptions())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Category "Extra" has no i18n key and is never translated.
+	expected := []string{
+		"MessageProfile.bio.description",
+		"MessageProfile.bio.label",
+		"MessageProfile.name.label",
+		"MessageProfile.secret.label",
+		"MessageProfile.status.enum.off",
+		"MessageProfile.status.enum.on",
+		"MessageProfile.status.label",
+		"profile.note",
+		"tabs.main",
+	}
+
+	if got := messageKeys(messages); !reflect.DeepEqual(got, expected) {
+		t.Fatalf("unexpected keys:\n got %v\nwant %v", got, expected)
+	}
+
+	name := messages[2]
+	if name.Source != "Full name" || !reflect.DeepEqual(name.Keys, []string{"MessageProfile.name.label", "Full name"}) {
+		t.Errorf("expected label source and alternative key, got %+v", name)
+	}
+
+	if bio := messages[0]; bio.Source != "About you" {
+		t.Errorf("expected description source, got %+v", bio)
+	}
+
+	for _, m := range messages {
+		if m.Optional != strings.Contains(m.Key, ".enum.") {
+			t.Errorf("expected only enum values to be optional, got %+v", m)
+		}
+	}
+
+	catalogue := parser.Catalogue(messages)
+	if catalogue["MessageProfile.status.enum.on"] != "on" || catalogue["profile.note"] != "note" || catalogue["tabs.main"] != "Main" {
+		t.Errorf("unexpected catalogue %v", catalogue)
+	}
+}
+
+func TestExtractMessages_IgnoresTranslator(t *testing.T) {
+	opts := schema.DefaultOptions()
+	opts.Translator = schema.NewMapTranslator(map[string]map[string]string{
+		"uk": {"MessageProfile.name.label": "Ім'я"},
+	})
+	opts.Locale = "uk"
+
+	messages, err := parser.ExtractMessages(MessageProfile{}, opts)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(messages) != 9 {
+		t.Errorf("expected all messages regardless of translations, got %v", messageKeys(messages))
+	}
+}
+
+func profileOptions() schema.Options {
+	opts := schema.DefaultOptions()
+	opts.Translator = schema.NewMapTranslator(map[string]map[string]string{
+		"en": {
+			"Full name":                      "Full Name",
+			"MessageProfile.bio.label":       "Bio",
+			"MessageProfile.bio.description": "About you",
+			"MessageProfile.status.label":    "Status",
+			"MessageProfile.status.enum.on":  "On",
+			"MessageProfile.status.enum.off": "Off",
+			"MessageProfile.secret.label":    "Secret",
+			"profile.note":                   "Note",
+			"tabs.main":                      "General",
+		},
+		"uk": {
+			"MessageProfile.name.label": "Ім'я",
+			"About you":                 "Про себе",
+			"profile.note":              "Примітка",
+		},
+	})
+
+	return opts
+}
+
+func TestMissingTranslations(t *testing.T) {
+	missing, err := parser.MissingTranslations(MessageProfile{}, profileOptions())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, ok := missing["en"]; ok {
+		t.Errorf("expected en to be complete (label used as key counts), got %v", missing["en"])
+	}
+
+	expected := []string{
+		"MessageProfile.bio.label",
+		"MessageProfile.secret.label",
+		"MessageProfile.status.label",
+		"tabs.main",
+	}
+
+	if !reflect.DeepEqual(missing["uk"], expected) {
+		t.Errorf("unexpected uk report:\n got %v\nwant %v", missing["uk"], expected)
+	}
+}
+
+func TestMissingTranslations_Locales(t *testing.T) {
+	missing, err := parser.MissingTranslations(MessageProfile{}, profileOptions(), "en", "de")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(missing) != 1 || len(missing["de"]) != 7 {
+		t.Errorf("expected only de to be reported, got %v", missing)
+	}
+
+	opts := schema.DefaultOptions()
+	opts.Translator = plainTranslator{}
+
+	if _, err := parser.MissingTranslations(MessageProfile{}, opts); !errors.Is(err, parser.ErrNoLocales) {
+		t.Errorf("expected ErrNoLocales, got %v", err)
+	}
+}
+
+type recordingReporter struct {
+	errors []string
+}
+
+func (r *recordingReporter) Helper() {}
+
+func (r *recordingReporter) Errorf(format string, args ...any) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func TestCheckTranslations(t *testing.T) {
+	if !parser.CheckTranslations(t, MessageProfile{}, profileOptions(), "en") {
+		t.Error("expected en to pass")
+	}
+
+	rec := &recordingReporter{}
+	if parser.CheckTranslations(rec, MessageProfile{}, profileOptions(), "uk") {
+		t.Error("expected uk to fail")
+	}
+
+	if len(rec.errors) != 4 {
+		t.Fatalf("expected one error per missing key, got %v", rec.errors)
+	}
+
+	if !strings.Contains(rec.errors[0], `missing "uk" translation for "MessageProfile.bio.label" ("bio")`) {
+		t.Errorf("unexpected message %q", rec.errors[0])
+	}
+}
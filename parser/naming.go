@@ -0,0 +1,134 @@
+package parser
+
+import (
+	"reflect"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/holdemlab/ui-json-schema/schema"
+)
+
+// TypeName returns a readable name for a Go type, usable as a definition
+// key, registry name or title. Package paths are dropped and generic
+// instantiations are spelled out:
+//
+//	Page[github.com/acme/x.User]     → PageOfUser
+//	Pair[string,int]                 → PairOfStringAndInt
+//	Page[Optional[*x.User]]          → PageOfOptionalOfUser
+//	Page[[]x.User]                   → PageOfUserList
+//	Index[map[string]x.User]         → IndexOfMapOfStringToUser
+//
+// Pointers are unwrapped. Returns "" for a nil type.
+func TypeName(t reflect.Type) string {
+	if t == nil {
+		return ""
+	}
+
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	return formatTypeName(t.String())
+}
+
+// TypeNameWithOptions names a type with opts.TypeNamer, falling back to
+// TypeName when no namer is set or it returns "". Pointers are unwrapped
+// before the namer is called.
+func TypeNameWithOptions(t reflect.Type, opts schema.Options) string {
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	return typeName(t, &opts)
+}
+
+// typeName names a type with Options.TypeNamer, falling back to TypeName
+// when no namer is set or it returns "".
+func typeName(t reflect.Type, opts *schema.Options) string {
+	if opts != nil && opts.TypeNamer != nil {
+		if name := opts.TypeNamer(t); name != "" {
+			return name
+		}
+	}
+
+	return TypeName(t)
+}
+
+// formatTypeName converts a reflect type string into a TypeName.
+func formatTypeName(s string) string {
+	s = strings.TrimLeft(strings.TrimSpace(s), "*")
+
+	switch {
+	case s == "interface {}":
+		return "Any"
+	case strings.HasPrefix(s, "struct {"):
+		return "Object"
+	case strings.HasPrefix(s, "map["):
+		end := closingBracket(s, len("map"))
+		return "MapOf" + formatTypeName(s[len("map["):end]) + "To" + formatTypeName(s[end+1:])
+	case strings.HasPrefix(s, "["):
+		// Slices ([]T) and arrays ([N]T).
+		return formatTypeName(s[strings.IndexByte(s, ']')+1:]) + "List"
+	}
+
+	head, args, generic := strings.Cut(s, "[")
+
+	name := head[strings.LastIndexAny(head, "/.")+1:]
+	if r, size := utf8.DecodeRuneInString(name); r != utf8.RuneError {
+		name = string(unicode.ToUpper(r)) + name[size:]
+	}
+
+	if !generic {
+		return name
+	}
+
+	parts := splitTypeArgs(strings.TrimSuffix(args, "]"))
+	for i, part := range parts {
+		parts[i] = formatTypeName(part)
+	}
+
+	return name + "Of" + strings.Join(parts, "And")
+}
+
+// closingBracket returns the index of the ']' matching the '[' at open.
+func closingBracket(s string, open int) int {
+	depth := 0
+
+	for i := open; i < len(s); i++ {
+		switch s[i] {
+		case '[':
+			depth++
+		case ']':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+
+	return len(s) - 1
+}
+
+// splitTypeArgs splits a generic argument list on top-level commas.
+func splitTypeArgs(s string) []string {
+	var parts []string
+
+	depth, start := 0, 0
+
+	for i := range len(s) {
+		switch s[i] {
+		case '[':
+			depth++
+		case ']':
+			depth--
+		case ',':
+			if depth == 0 {
+				parts = append(parts, s[start:i])
+				start = i + 1
+			}
+		}
+	}
+
+	return append(parts, s[start:])
+}
@@ -0,0 +1,110 @@
+package parser_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/holdemlab/ui-json-schema/parser"
+	"github.com/holdemlab/ui-json-schema/schema"
+)
+
+type Page[T any] struct {
+	Items []T `json:"items"`
+	Total int `json:"total"`
+}
+
+type Optional[T any] struct {
+	Value T    `json:"value"`
+	Set   bool `json:"set"`
+}
+
+type Pair[K comparable, V any] struct {
+	Key   K `json:"key"`
+	Value V `json:"value"`
+}
+
+type NamingUser struct {
+	Name string `json:"name"`
+}
+
+func TestTypeName(t *testing.T) {
+	tests := []struct {
+		name string
+		typ  reflect.Type
+		want string
+	}{
+		{"plain struct", reflect.TypeOf(NamingUser{}), "NamingUser"},
+		{"pointer", reflect.TypeOf(&NamingUser{}), "NamingUser"},
+		{"builtin", reflect.TypeOf(""), "String"},
+		{"generic", reflect.TypeOf(Page[NamingUser]{}), "PageOfNamingUser"},
+		{"generic pointer arg", reflect.TypeOf(Page[*NamingUser]{}), "PageOfNamingUser"},
+		{"nested generic", reflect.TypeOf(Page[Optional[NamingUser]]{}), "PageOfOptionalOfNamingUser"},
+		{"two args", reflect.TypeOf(Pair[string, int]{}), "PairOfStringAndInt"},
+		{"slice arg", reflect.TypeOf(Page[[]NamingUser]{}), "PageOfNamingUserList"},
+		{"map arg", reflect.TypeOf(Optional[map[string]NamingUser]{}), "OptionalOfMapOfStringToNamingUser"},
+		{"any arg", reflect.TypeOf(Optional[any]{}), "OptionalOfAny"},
+		{"anonymous struct", reflect.TypeOf(struct{ A int }{}), "Object"},
+		{"nil", nil, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parser.TypeName(tt.typ); got != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestRootTitle(t *testing.T) {
+	opts := schema.DefaultOptions()
+	opts.RootTitle = true
+
+	s, err := parser.GenerateJSONSchemaWithOptions(Page[NamingUser]{}, opts)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if s.Title != "PageOfNamingUser" {
+		t.Errorf("expected title PageOfNamingUser, got %q", s.Title)
+	}
+
+	s, err = parser.GenerateJSONSchema(Page[NamingUser]{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if s.Title != "" {
+		t.Errorf("expected no title by default, got %q", s.Title)
+	}
+}
+
+func TestRootTitle_CustomNamer(t *testing.T) {
+	opts := schema.DefaultOptions()
+	opts.RootTitle = true
+	opts.TypeNamer = func(t reflect.Type) string {
+		if t == reflect.TypeOf(Page[NamingUser]{}) {
+			return "UserPage"
+		}
+
+		return ""
+	}
+
+	s, err := parser.GenerateJSONSchemaWithOptions(&Page[NamingUser]{}, opts)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if s.Title != "UserPage" {
+		t.Errorf("expected custom title UserPage, got %q", s.Title)
+	}
+
+	s, err = parser.GenerateJSONSchemaWithOptions(Optional[NamingUser]{}, opts)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if s.Title != "OptionalOfNamingUser" {
+		t.Errorf("expected fallback title, got %q", s.Title)
+	}
+}
@@ -4,6 +4,7 @@
 package parser
 
 import (
+	"encoding/json"
 	"reflect"
 	"strings"
 	"time"
@@ -14,9 +15,15 @@ import (
 // timeType is cached to avoid repeated reflect.TypeOf calls.
 var timeType = reflect.TypeOf(time.Time{})
 
+// rawMessageType is cached to avoid repeated reflect.TypeOf calls.
+var rawMessageType = reflect.TypeOf(json.RawMessage(nil))
+
 // layoutHorizontal is the form tag value for horizontal layout grouping.
 const layoutHorizontal = "horizontal"
 
+// otherCategoryLabel is the label of the Category holding uncategorized elements.
+const otherCategoryLabel = "Other"
+
 // GenerateJSONSchema generates a JSON Schema (Draft 7) from a Go value.
 // The value should be a struct or a pointer to a struct.
 func GenerateJSONSchema(v any) (*schema.JSONSchema, error) {
@@ -24,7 +31,20 @@ func GenerateJSONSchema(v any) (*schema.JSONSchema, error) {
 }
 
 // GenerateJSONSchemaWithOptions generates a JSON Schema using the supplied options.
+// With OmitEmpty or ValueDefaults set, the field values of v are inspected
+// as well as its type.
 func GenerateJSONSchemaWithOptions(v any, opts schema.Options) (*schema.JSONSchema, error) {
+	root, t := fullJSONSchema(v, &opts)
+	if t.Kind() == reflect.Struct {
+		applySchemaAccess(root, t, &opts)
+	}
+
+	return root, nil
+}
+
+// fullJSONSchema generates the JSON Schema of v before role permissions
+// are applied, and returns it with the type of v.
+func fullJSONSchema(v any, opts *schema.Options) (*schema.JSONSchema, reflect.Type) {
 	t := reflect.TypeOf(v)
 	if t.Kind() == reflect.Ptr {
 		t = t.Elem()
@@ -37,14 +57,19 @@ func GenerateJSONSchemaWithOptions(v any, opts schema.Options) (*schema.JSONSche
 	root.Properties = make(map[string]*schema.JSONSchema)
 
 	if t.Kind() == reflect.Struct {
-		parseStructFields(t, root)
+		parseStructFields(t, rootValue(v), root, opts)
+
+		if opts.RootTitle {
+			root.Title = typeTitle(t, opts)
+		}
 	}
 
-	return root, nil
+	return root, t
 }
 
 // parseStructFields iterates over struct fields and populates the schema properties.
-func parseStructFields(t reflect.Type, s *schema.JSONSchema) {
+// v holds the struct value, or the zero reflect.Value when only the type is known.
+func parseStructFields(t reflect.Type, v reflect.Value, s *schema.JSONSchema, opts *schema.Options) {
 	for i := range t.NumField() {
 		field := t.Field(i)
 
@@ -58,11 +83,19 @@ func parseStructFields(t reflect.Type, s *schema.JSONSchema) {
 			continue
 		}
 
-		prop := typeToSchema(field.Type)
+		fv := fieldValue(v, field.Index)
+		if isOmittedField(field, fv, opts) {
+			continue
+		}
+
+		prop := valueToSchema(field.Type, fv, opts)
 
 		// Apply struct tags to the property.
 		tags := schema.ParseFieldTags(field)
 		applyTags(prop, tags)
+		applyValueDefault(prop, fv, opts)
+		localizeProperty(prop, t, name, tags, opts)
+		applyErrorMessages(prop, s, t, name, tags, opts)
 
 		// Add to required list if tagged.
 		if tags.Required {
@@ -110,6 +143,8 @@ func applyTags(prop *schema.JSONSchema, tags schema.FieldTags) {
 	if tags.Pattern != "" {
 		prop.Pattern = tags.Pattern
 	}
+
+	applyKeyTags(prop, tags)
 }
 
 // fieldJSONName returns the JSON field name from the json struct tag.
@@ -128,8 +163,29 @@ func fieldJSONName(field reflect.StructField) string {
 	return name
 }
 
-// typeToSchema converts a reflect.Type to a JSONSchema property.
-func typeToSchema(t reflect.Type) *schema.JSONSchema {
+// typeToSchema converts a reflect.Type to a JSONSchema property, Nullable
+// when encoding/json may write null for it. opts localizes nested struct
+// fields; it may be nil.
+func typeToSchema(t reflect.Type, opts *schema.Options) *schema.JSONSchema {
+	s := kindToSchema(t, opts)
+	s.Nullable = isNullable(t)
+
+	return s
+}
+
+// isNullable reports whether encoding/json writes null for the zero value
+// of t.
+func isNullable(t reflect.Type) bool {
+	switch t.Kind() { //nolint:exhaustive // other kinds are never null
+	case reflect.Ptr, reflect.Slice, reflect.Map, reflect.Interface:
+		return true
+	default:
+		return false
+	}
+}
+
+// kindToSchema converts a reflect.Type to a JSONSchema by kind.
+func kindToSchema(t reflect.Type, opts *schema.Options) *schema.JSONSchema {
 	// Unwrap pointer types.
 	if t.Kind() == reflect.Ptr {
 		t = t.Elem()
@@ -143,6 +199,19 @@ func typeToSchema(t reflect.Type) *schema.JSONSchema {
 		}
 	}
 
+	// json.RawMessage holds arbitrary JSON.
+	if t == rawMessageType {
+		return &schema.JSONSchema{}
+	}
+
+	// Byte slices are encoded by encoding/json as base64 strings.
+	if t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8 {
+		return &schema.JSONSchema{
+			Type:            "string",
+			ContentEncoding: "base64",
+		}
+	}
+
 	switch t.Kind() { //nolint:exhaustive // only JSON-representable types are handled
 	case reflect.String:
 		return &schema.JSONSchema{Type: "string"}
@@ -159,22 +228,34 @@ func typeToSchema(t reflect.Type) *schema.JSONSchema {
 	case reflect.Float32, reflect.Float64:
 		return &schema.JSONSchema{Type: "number"}
 
-	case reflect.Slice, reflect.Array:
-		items := typeToSchema(t.Elem())
+	case reflect.Slice:
+		items := typeToSchema(t.Elem(), opts)
 		return &schema.JSONSchema{
 			Type:  "array",
 			Items: items,
 		}
 
+	case reflect.Array:
+		// Fixed-size arrays always hold exactly N items.
+		n := t.Len()
+		return &schema.JSONSchema{
+			Type:     "array",
+			Items:    typeToSchema(t.Elem(), opts),
+			MinItems: &n,
+			MaxItems: &n,
+		}
+
 	case reflect.Map:
-		if t.Key().Kind() != reflect.String {
+		keys, ok := mapKeySchema(t.Key())
+		if !ok {
 			return &schema.JSONSchema{Type: "object"}
 		}
 
-		additional := typeToSchema(t.Elem())
+		additional := typeToSchema(t.Elem(), opts)
 		return &schema.JSONSchema{
 			Type:                 "object",
 			AdditionalProperties: additional,
+			PropertyNames:        keys,
 		}
 
 	case reflect.Struct:
@@ -182,9 +263,12 @@ func typeToSchema(t reflect.Type) *schema.JSONSchema {
 			Type:       "object",
 			Properties: make(map[string]*schema.JSONSchema),
 		}
-		parseStructFields(t, obj)
+		parseStructFields(t, reflect.Value{}, obj, opts)
 		return obj
 
+	case reflect.Interface:
+		return interfaceToSchema(t, opts)
+
 	default:
 		return &schema.JSONSchema{Type: "string"}
 	}
@@ -197,95 +281,177 @@ func GenerateUISchema(v any) (*schema.UISchemaElement, error) {
 }
 
 // GenerateUISchemaWithOptions generates a JSON Forms UI Schema using the supplied options.
+// With OmitEmpty set, empty omitempty fields of v are left out.
 func GenerateUISchemaWithOptions(v any, opts schema.Options) (*schema.UISchemaElement, error) {
 	t := reflect.TypeOf(v)
 	if t.Kind() == reflect.Ptr {
 		t = t.Elem()
 	}
 
+	if opts.Template != nil && t.Kind() == reflect.Struct {
+		root, err := buildFromTemplate(t, rootValue(v), opts.Template, &opts)
+		if err != nil {
+			return nil, err
+		}
+
+		applyUIAccess(root, t, &opts)
+		stripInternalOptions(root)
+
+		return root, nil
+	}
+
 	root := schema.NewVerticalLayout()
 
 	if t.Kind() == reflect.Struct {
-		buildUIElements(t, "#/properties", root, &opts)
+		buildUIElements(t, rootValue(v), "#/properties", root, &opts)
+		applyUIAccess(root, t, &opts)
 	}
 
 	// If any fields have categories, wrap elements into a Categorization.
 	if hasCategorizedElements(root) {
-		return buildCategorization(root, &opts), nil
+		root = buildCategorization(root, &opts)
+	} else {
+		// Apply horizontal grouping on the root layout.
+		root.Elements = groupHorizontalElements(root.Elements)
 	}
 
-	// Apply horizontal grouping on the root layout.
-	root.Elements = groupHorizontalElements(root.Elements)
+	// Remove any generation hints that were not consumed by a layout pass.
+	stripInternalOptions(root)
 
 	return root, nil
 }
 
 // buildUIElements iterates over struct fields and builds UI Schema elements.
-func buildUIElements(t reflect.Type, basePath string, parent *schema.UISchemaElement, opts *schema.Options) {
+// Elements are stably sorted by the form tag "order=" value and fields that
+// share a "group=" name are wrapped into a single Group. v holds the struct
+// value, or the zero reflect.Value when only the type is known.
+func buildUIElements(t reflect.Type, v reflect.Value, basePath string, parent *schema.UISchemaElement, opts *schema.Options) {
+	built := make([]builtElement, 0, t.NumField())
+
 	for i := range t.NumField() {
 		field := t.Field(i)
 
-		if !field.IsExported() {
+		el, formOpts := buildFieldElement(t, field, fieldValue(v, field.Index), basePath, opts)
+		if el == nil {
 			continue
 		}
 
-		name := fieldJSONName(field)
-		if name == "-" {
-			continue
-		}
+		built = append(built, builtElement{el: el, formOpts: formOpts})
+	}
 
-		tags := schema.ParseFieldTags(field)
-		formOpts := schema.ParseFormTag(tags.Form)
+	sortByOrder(built)
 
-		if isFieldHidden(name, formOpts, opts) {
-			continue
-		}
+	parent.Elements = append(parent.Elements, wrapNamedGroups(built, opts)...)
+}
 
-		scope := basePath + "/" + name
-		fieldType := field.Type
-		if fieldType.Kind() == reflect.Ptr {
-			fieldType = fieldType.Elem()
-		}
+// buildFieldElement builds the UI Schema element for a single struct field:
+// a Group for nested structs, a Control with options.detail for slices of
+// structs, or a plain Control. Returns nil when the field is skipped.
+// owner is the struct type declaring the field.
+func buildFieldElement(owner reflect.Type, field reflect.StructField, v reflect.Value, basePath string,
+	opts *schema.Options) (*schema.UISchemaElement, schema.FormOptions) {
+	if !field.IsExported() {
+		return nil, schema.FormOptions{}
+	}
 
-		// Nested structs (excluding time.Time) get a Group layout.
-		if fieldType.Kind() == reflect.Struct && fieldType != timeType {
-			label := formOpts.Label
-			if label == "" {
-				label = field.Name
-			}
+	name := fieldJSONName(field)
+	if name == "-" {
+		return nil, schema.FormOptions{}
+	}
 
-			label = translateLabel(label, tags.I18nKey, opts)
+	tags := schema.ParseFieldTags(field)
+	formOpts := schema.ParseFormTag(tags.Form)
 
-			group := schema.NewGroup(label)
+	if formOpts.Hidden || isOmittedField(field, v, opts) {
+		return nil, formOpts
+	}
 
-			buildUIElements(fieldType, scope+"/properties", group, opts)
-			// Apply horizontal grouping within nested groups immediately,
-			// as groups are not affected by categorization.
-			group.Elements = groupHorizontalElements(group.Elements)
-			// Apply rule from the struct field tags to the Group element.
-			applyRule(group, tags)
-			// Propagate category, category rule & i18n from the form tag
-			// so nested structs are placed into the correct Category.
-			applyGroupCategoryOptions(group, formOpts)
-			parent.Elements = append(parent.Elements, group)
+	scope := basePath + "/" + name
+	fieldType := field.Type
+	if fieldType.Kind() == reflect.Ptr {
+		fieldType = fieldType.Elem()
+	}
 
-			continue
+	// Nested structs (excluding time.Time) get a Group layout.
+	if fieldType.Kind() == reflect.Struct && fieldType != timeType {
+		label := formOpts.Label
+		if label == "" {
+			label = field.Name
 		}
 
-		// Slice/array of structs → Control with options.detail containing
-		// the UI Schema for array items (JSON Forms convention).
-		if elemType, ok := sliceOfStructsElemType(fieldType); ok {
-			control := buildArrayControl(scope, name, formOpts, tags, opts, elemType)
-			parent.Elements = append(parent.Elements, control)
+		label = fieldLabel(owner, name, label, tags.I18nKey, opts)
 
-			continue
+		group := schema.NewGroup(label)
+
+		buildUIElements(fieldType, structValue(v), scope+"/properties", group, opts)
+		// Categories declared inside the nested struct form the group's
+		// own Categorization; otherwise apply horizontal grouping within
+		// the group immediately, as it is not affected by the parent's
+		// categorization.
+		if hasCategorizedElements(group) {
+			group.Elements = []*schema.UISchemaElement{buildCategorization(group, opts)}
+		} else {
+			group.Elements = groupHorizontalElements(group.Elements)
+		}
+		// Apply rule from the struct field tags to the Group element.
+		applyRule(group, tags)
+		// Propagate category, category rule & i18n from the form tag
+		// so nested structs are placed into the correct Category.
+		applyGroupCategoryOptions(group, formOpts)
+
+		return group, formOpts
+	}
+
+	// Slice/array of structs → Control with options.detail containing
+	// the UI Schema for array items (JSON Forms convention).
+	if elemType, ok := sliceOfStructsElemType(fieldType); ok {
+		return buildArrayControl(owner, scope, name, formOpts, tags, opts, elemType), formOpts
+	}
+
+	// Map of structs → key/value editor Control with options.detail
+	// containing the UI Schema for the map values.
+	if elemType, ok := mapOfStructsElemType(fieldType); ok {
+		return buildMapControl(owner, scope, name, formOpts, tags, opts, elemType), formOpts
+	}
+
+	// Interfaces with registered variants get a Group holding a variant
+	// selector and one detail Group per variant.
+	if set, ok := lookupVariants(fieldType); ok {
+		label := formOpts.Label
+		if label == "" {
+			label = field.Name
 		}
 
-		control := buildControl(scope, name, formOpts, tags, opts)
+		group := schema.NewGroup(fieldLabel(owner, name, label, tags.I18nKey, opts))
+		group.Elements = buildVariantElements(set, scope+"/properties", opts)
+		applyRule(group, tags)
+		applyGroupCategoryOptions(group, formOpts)
+
+		return group, formOpts
+	}
+
+	// Slice/array of registered interfaces → Control whose options.detail
+	// holds the variant selector and variant Groups for each item.
+	if set, ok := sliceOfVariantsSet(fieldType); ok {
+		control := buildControl(owner, scope, name, formOpts, tags, opts)
+		detail := schema.NewVerticalLayout()
+		detail.Elements = buildVariantElements(set, "#/properties", opts)
+
+		ensureOptions(control)
+		control.Options["detail"] = detail
 		applyLayoutOptions(control, formOpts)
 
-		parent.Elements = append(parent.Elements, control)
+		return control, formOpts
 	}
+
+	prop := typeToSchema(field.Type, nil)
+	applyTags(prop, tags)
+
+	control := buildControl(owner, scope, name, formOpts, tags, opts)
+	applyWidgetHints(control, prop, formOpts, opts)
+	applyLayoutOptions(control, formOpts)
+
+	return control, formOpts
 }
 
 // sliceOfStructsElemType checks if the type is a slice/array whose element
@@ -308,38 +474,37 @@ func sliceOfStructsElemType(t reflect.Type) (reflect.Type, bool) {
 	return nil, false
 }
 
-// buildArrayDetail builds a VerticalLayout with Controls for the fields of
-// an array item struct. The resulting element is intended for use as
-// options.detail in a JSON Forms array Control.
+// sliceOfVariantsSet checks if the type is a slice/array of an interface
+// with registered variants and returns its variant set.
+func sliceOfVariantsSet(t reflect.Type) (*variantSet, bool) {
+	if t.Kind() != reflect.Slice && t.Kind() != reflect.Array {
+		return nil, false
+	}
+
+	return lookupVariants(t.Elem())
+}
+
+// buildArrayDetail builds a VerticalLayout (or a Categorization, when item
+// fields declare categories) with Controls for the fields of an array item
+// struct. The resulting element is intended for use as options.detail in a
+// JSON Forms array Control.
 func buildArrayDetail(elemType reflect.Type, opts *schema.Options) *schema.UISchemaElement {
 	detail := schema.NewVerticalLayout()
-	buildUIElements(elemType, "#/properties", detail, opts)
-
-	// Apply horizontal grouping inside the detail layout.
-	detail.Elements = groupHorizontalElements(detail.Elements)
+	buildUIElements(elemType, reflect.Value{}, "#/properties", detail, opts)
 
 	if len(detail.Elements) == 0 {
 		return nil
 	}
 
-	return detail
-}
-
-// isFieldHidden determines whether a field should be excluded from the UI Schema.
-func isFieldHidden(name string, formOpts schema.FormOptions, opts *schema.Options) bool {
-	if formOpts.Hidden {
-		return true
+	// Categories declared on item fields turn the detail into a Categorization.
+	if hasCategorizedElements(detail) {
+		return buildCategorization(detail, opts)
 	}
 
-	if opts != nil && opts.Role != "" {
-		if perms, ok := opts.RolePermissions[opts.Role]; ok {
-			if level, exists := perms[name]; exists && level == schema.AccessHidden {
-				return true
-			}
-		}
-	}
+	// Apply horizontal grouping inside the detail layout.
+	detail.Elements = groupHorizontalElements(detail.Elements)
 
-	return false
+	return detail
 }
 
 // applyLayoutOptions sets the internal layout and layoutGroup options on
@@ -359,9 +524,9 @@ func applyLayoutOptions(control *schema.UISchemaElement, formOpts schema.FormOpt
 
 // buildArrayControl creates a Control for a slice-of-structs field with
 // options.detail containing the UI Schema for the array items.
-func buildArrayControl(scope, name string, formOpts schema.FormOptions, tags schema.FieldTags,
+func buildArrayControl(owner reflect.Type, scope, name string, formOpts schema.FormOptions, tags schema.FieldTags,
 	opts *schema.Options, elemType reflect.Type) *schema.UISchemaElement {
-	control := buildControl(scope, name, formOpts, tags, opts)
+	control := buildControl(owner, scope, name, formOpts, tags, opts)
 	detail := buildArrayDetail(elemType, opts)
 
 	if detail != nil {
@@ -374,11 +539,13 @@ func buildArrayControl(scope, name string, formOpts schema.FormOptions, tags sch
 	return control
 }
 
-// buildControl creates a fully configured Control UI Schema element.
-func buildControl(scope, name string, formOpts schema.FormOptions, tags schema.FieldTags, opts *schema.Options) *schema.UISchemaElement {
+// buildControl creates a fully configured Control UI Schema element for
+// the field name of the owner struct type.
+func buildControl(owner reflect.Type, scope, name string, formOpts schema.FormOptions, tags schema.FieldTags,
+	opts *schema.Options) *schema.UISchemaElement {
 	control := schema.NewControl(scope)
 
-	controlLabel := translateLabel(formOpts.Label, tags.I18nKey, opts)
+	controlLabel := fieldLabel(owner, name, formOpts.Label, tags.I18nKey, opts)
 	if controlLabel != "" {
 		control.Label = controlLabel
 	}
@@ -388,12 +555,10 @@ func buildControl(scope, name string, formOpts schema.FormOptions, tags schema.F
 		control.Options["category"] = formOpts.Category
 	}
 
-	isReadonly := formOpts.Readonly || isRoleReadOnly(name, opts)
-
-	if isReadonly || formOpts.Multiline {
+	if formOpts.Readonly || formOpts.Multiline {
 		ensureOptions(control)
 
-		if isReadonly {
+		if formOpts.Readonly {
 			control.Options["readonly"] = true
 		}
 
@@ -408,6 +573,8 @@ func buildControl(scope, name string, formOpts schema.FormOptions, tags schema.F
 		control.Options["renderer"] = renderer
 	}
 
+	// Pass JSON Forms options from the form tag through verbatim.
+	mergeOptions(control, formOpts.Options)
 	applyRule(control, tags)
 	applyCategoryRuleOptions(control, formOpts)
 	applyCategoryI18nOption(control, formOpts)
@@ -422,19 +589,17 @@ func ensureOptions(el *schema.UISchemaElement) {
 	}
 }
 
-// isRoleReadOnly checks whether the active role requires a field to be readonly.
-func isRoleReadOnly(name string, opts *schema.Options) bool {
-	if opts == nil || opts.Role == "" {
-		return false
+// mergeOptions copies options onto an element, overriding existing keys.
+func mergeOptions(el *schema.UISchemaElement, options map[string]any) {
+	if len(options) == 0 {
+		return
 	}
 
-	if perms, ok := opts.RolePermissions[opts.Role]; ok {
-		if level, exists := perms[name]; exists && level == schema.AccessReadOnly {
-			return true
-		}
-	}
+	ensureOptions(el)
 
-	return false
+	for k, v := range options {
+		el.Options[k] = v
+	}
 }
 
 // resolveRenderer determines the renderer name from a tag or from options.
@@ -471,7 +636,16 @@ func translateLabel(label, i18nKey string, opts *schema.Options) string {
 		return ""
 	}
 
-	return opts.Translator.Translate(key, opts.Locale)
+	source := label
+	if source == "" {
+		source = key
+	}
+
+	if text, ok := resolveText(opts, source, key); ok {
+		return text
+	}
+
+	return key
 }
 
 // hasCategorizedElements checks if any element has a category option set.
@@ -638,13 +812,17 @@ func applyGroupCategoryOptions(group *schema.UISchemaElement, formOpts schema.Fo
 
 // buildCategorization groups elements by their category option
 // into a Categorization layout. Elements without a category are
-// placed into an "Other" category.
+// placed into the fallback category ("Other" unless configured in
+// Options.Categorization).
 func buildCategorization(root *schema.UISchemaElement, opts *schema.Options) *schema.UISchemaElement {
+	catOpts := opts.Categorization
+	otherName := otherCategoryName(opts)
+
 	catMap := make(map[string]*schema.UISchemaElement)
 	catOrder := make([]string, 0)
 
 	for _, el := range root.Elements {
-		catName := "Other"
+		catName := ""
 
 		if el.Options != nil {
 			if c, ok := el.Options["category"].(string); ok && c != "" {
@@ -658,6 +836,14 @@ func buildCategorization(root *schema.UISchemaElement, opts *schema.Options) *sc
 			}
 		}
 
+		if catName == "" {
+			if catOpts.OmitOther {
+				continue
+			}
+
+			catName = otherName
+		}
+
 		if _, exists := catMap[catName]; !exists {
 			catMap[catName] = schema.NewCategory(catName)
 			catOrder = append(catOrder, catName)
@@ -667,14 +853,22 @@ func buildCategorization(root *schema.UISchemaElement, opts *schema.Options) *sc
 	}
 
 	categorization := schema.NewCategorization()
-	for _, name := range catOrder {
+	for _, name := range sortCategoryNames(catOrder, catOpts.Order) {
 		cat := catMap[name]
 		cat.Elements = groupHorizontalElements(cat.Elements)
 		extractCategoryRule(cat)
 		extractCategoryI18n(cat, opts)
+
+		if name == otherName && cat.I18n == "" {
+			localizeOtherCategory(cat, opts)
+		}
+
+		mergeOptions(cat, catOpts.Categories[name])
 		categorization.Elements = append(categorization.Elements, cat)
 	}
 
+	applyCategorizationOptions(categorization, opts)
+
 	return categorization
 }
 
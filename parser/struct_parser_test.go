@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/holdemlab/ui-json-schema/parser"
+	"github.com/holdemlab/ui-json-schema/schema"
 )
 
 const (
@@ -1408,3 +1409,110 @@ func assertSchemaType(t *testing.T, got, expected string) {
 		t.Errorf("expected type %q, got %q", expected, got)
 	}
 }
+
+type BinaryStruct struct {
+	Avatar  []byte          `json:"avatar"`
+	Payload json.RawMessage `json:"payload"`
+	Hash    [32]byte        `json:"hash"`
+	Point   [3]float64      `json:"point"`
+	Images  [][]byte        `json:"images"`
+}
+
+func TestGenerateJSONSchema_BytesAndArrays(t *testing.T) {
+	s, err := parser.GenerateJSONSchema(BinaryStruct{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	avatar := s.Properties["avatar"]
+	if avatar.Type != typeString || avatar.ContentEncoding != "base64" || avatar.Items != nil {
+		t.Errorf("expected base64 string for []byte, got %+v", avatar)
+	}
+
+	payload := s.Properties["payload"]
+	if payload.Type != "" || payload.Items != nil || payload.ContentEncoding != "" {
+		t.Errorf("expected unconstrained schema for json.RawMessage, got %+v", payload)
+	}
+
+	point := s.Properties["point"]
+	if point.Type != "array" || point.Items.Type != "number" {
+		t.Fatalf("expected array of numbers, got %+v", point)
+	}
+
+	if point.MinItems == nil || *point.MinItems != 3 || point.MaxItems == nil || *point.MaxItems != 3 {
+		t.Errorf("expected minItems = maxItems = 3, got %v / %v", point.MinItems, point.MaxItems)
+	}
+
+	// encoding/json writes [N]byte as an array of numbers, not base64.
+	hash := s.Properties["hash"]
+	if hash.Type != "array" || hash.Items.Type != "integer" || *hash.MaxItems != 32 {
+		t.Errorf("expected fixed array of integers for [32]byte, got %+v", hash)
+	}
+
+	images := s.Properties["images"]
+	if images.Type != "array" || images.Items.ContentEncoding != "base64" || images.MinItems != nil {
+		t.Errorf("expected array of base64 strings, got %+v", images)
+	}
+}
+
+func TestGenerateUISchema_BytesFileHint(t *testing.T) {
+	type Form struct {
+		Avatar  []byte          `json:"avatar" form:"accept=image/*"`
+		Payload json.RawMessage `json:"payload"`
+		Raw     []byte          `json:"raw" form:"widget=none"`
+	}
+
+	ui, err := parser.GenerateUISchema(Form{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	avatar := ui.Elements[0]
+	if avatar.Options["format"] != "file" || avatar.Options["accept"] != "image/*" {
+		t.Errorf("expected file hint with accept, got %v", avatar.Options)
+	}
+
+	if payload := ui.Elements[1]; payload.Options != nil {
+		t.Errorf("expected no hint for json.RawMessage, got %v", payload.Options)
+	}
+
+	if raw := ui.Elements[2]; raw.Options != nil {
+		t.Errorf("expected widget=none to suppress the file hint, got %v", raw.Options)
+	}
+}
+
+func TestGenerateJSONSchema_ZeroValueValidates(t *testing.T) {
+	type Inner struct {
+		Name string `json:"name"`
+	}
+
+	type Form struct {
+		Name    *string           `json:"name" required:"true" minLength:"2"`
+		Tags    []string          `json:"tags" minItems:"1"`
+		Labels  map[string]string `json:"labels"`
+		Inner   *Inner            `json:"inner"`
+		Items   []*Inner          `json:"items"`
+		Avatar  []byte            `json:"avatar"`
+		Extra   any               `json:"extra"`
+		Created *time.Time        `json:"created"`
+		Status  *string           `json:"status" enum:"on,off"`
+	}
+
+	js, err := parser.GenerateJSONSchema(Form{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	data, err := json.Marshal(Form{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if errs := schema.Validate(js, json.RawMessage(data)); errs != nil {
+		t.Errorf("expected the zero value %s to validate, got %v", data, errs)
+	}
+
+	if errs := schema.Validate(js, json.RawMessage(`{"name": null, "tags": 1}`)); len(errs) != 1 || errs[0].InstancePath != "/tags" {
+		t.Errorf("expected non-null values to keep their type, got %v", errs)
+	}
+}
@@ -0,0 +1,224 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/holdemlab/ui-json-schema/schema"
+)
+
+// ErrUnknownTemplateField is returned when a UISchemaTemplate references a
+// field path that does not exist in the struct.
+var ErrUnknownTemplateField = errors.New("template references unknown field")
+
+// templateBuilder builds a UI Schema for a struct type following a
+// UISchemaTemplate and tracks which field paths the template used.
+type templateBuilder struct {
+	t    reflect.Type
+	v    reflect.Value
+	opts *schema.Options
+	// used holds the dotted paths of fields referenced by the template.
+	used map[string]bool
+	// partial holds the dotted paths of structs with referenced descendants.
+	partial map[string]bool
+}
+
+// buildFromTemplate builds the UI Schema for struct type t laid out by tmpl.
+// Fields not referenced by the template are appended after the template
+// layout (inside the fallback Category when the root is a Categorization).
+// v holds the struct value, or the zero reflect.Value when only the type
+// is known.
+func buildFromTemplate(t reflect.Type, v reflect.Value, tmpl *schema.UISchemaTemplate, opts *schema.Options) (*schema.UISchemaElement, error) {
+	b := &templateBuilder{t: t, v: v, opts: opts, used: make(map[string]bool), partial: make(map[string]bool)}
+
+	root, err := b.build(tmpl)
+	if err != nil {
+		return nil, err
+	}
+
+	if root == nil || root.Type == "Control" {
+		layout := schema.NewVerticalLayout()
+		if root != nil {
+			layout.Elements = append(layout.Elements, root)
+		}
+
+		root = layout
+	}
+
+	if root.Type == "Categorization" {
+		applyCategorizationOptions(root, opts)
+	}
+
+	rest := b.remainingElements(t, v, "", "#/properties")
+	if len(rest) == 0 {
+		return root, nil
+	}
+
+	switch {
+	case root.Type != "Categorization":
+		root.Elements = append(root.Elements, rest...)
+	case !opts.Categorization.OmitOther:
+		other := newOtherCategory(opts)
+		other.Elements = rest
+		root.Elements = append(root.Elements, other)
+	}
+
+	return root, nil
+}
+
+// build converts a template node into a UI Schema element.
+// Returns nil for fields hidden by the form tag or the active role.
+func (b *templateBuilder) build(node *schema.UISchemaTemplate) (*schema.UISchemaElement, error) {
+	if node.Field != "" {
+		return b.buildField(node)
+	}
+
+	el := &schema.UISchemaElement{
+		Type:     node.Type,
+		Label:    translateLabel(node.Label, node.I18n, b.opts),
+		I18n:     node.I18n,
+		Rule:     node.Rule,
+		Elements: make([]*schema.UISchemaElement, 0, len(node.Elements)),
+	}
+
+	if el.Type == "" {
+		el.Type = "VerticalLayout"
+	}
+
+	mergeOptions(el, node.Options)
+
+	for _, child := range node.Elements {
+		childEl, err := b.build(child)
+		if err != nil {
+			return nil, err
+		}
+
+		if childEl != nil {
+			el.Elements = append(el.Elements, childEl)
+		}
+	}
+
+	return el, nil
+}
+
+// buildField resolves a field node to the element generated for that field.
+func (b *templateBuilder) buildField(node *schema.UISchemaTemplate) (*schema.UISchemaElement, error) {
+	path := strings.Split(node.Field, ".")
+
+	owner, field, fv, basePath, ok := lookupFieldPath(b.t, b.v, path)
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownTemplateField, node.Field)
+	}
+
+	b.used[node.Field] = true
+	for i := 1; i < len(path); i++ {
+		b.partial[strings.Join(path[:i], ".")] = true
+	}
+
+	el, _ := buildFieldElement(owner, field, fv, basePath, b.opts)
+	if el == nil {
+		return nil, nil
+	}
+
+	if node.Label != "" || node.I18n != "" {
+		el.Label = translateLabel(node.Label, node.I18n, b.opts)
+	}
+
+	mergeOptions(el, node.Options)
+
+	if node.Rule != nil {
+		el.Rule = node.Rule
+	}
+
+	return el, nil
+}
+
+// remainingElements builds the elements for fields that the template did
+// not reference, honoring order and group form tags. Nested structs with
+// some referenced fields keep a Group holding only the remaining ones.
+func (b *templateBuilder) remainingElements(t reflect.Type, v reflect.Value, prefix, basePath string) []*schema.UISchemaElement {
+	built := make([]builtElement, 0)
+
+	for i := range t.NumField() {
+		field := t.Field(i)
+		name := fieldJSONName(field)
+		path := prefix + name
+
+		if b.used[path] {
+			continue
+		}
+
+		fv := fieldValue(v, field.Index)
+
+		el, formOpts := buildFieldElement(t, field, fv, basePath, b.opts)
+		if el == nil {
+			continue
+		}
+
+		if b.partial[path] {
+			fieldType := field.Type
+			if fieldType.Kind() == reflect.Ptr {
+				fieldType = fieldType.Elem()
+			}
+
+			el.Elements = b.remainingElements(fieldType, structValue(fv), path+".", basePath+"/"+name+"/properties")
+			if len(el.Elements) == 0 {
+				continue
+			}
+		}
+
+		built = append(built, builtElement{el: el, formOpts: formOpts})
+	}
+
+	sortByOrder(built)
+
+	return groupHorizontalElements(wrapNamedGroups(built, b.opts))
+}
+
+// lookupFieldPath finds the struct field addressed by a dotted JSON path
+// and returns it together with the struct type declaring it, its value
+// (when v is known) and the scope base path of its parent.
+func lookupFieldPath(t reflect.Type, v reflect.Value, path []string) (reflect.Type, reflect.StructField, reflect.Value, string, bool) {
+	basePath := "#/properties"
+
+	for i, segment := range path {
+		field, ok := fieldByJSONName(t, segment)
+		if !ok {
+			return nil, reflect.StructField{}, reflect.Value{}, "", false
+		}
+
+		v = fieldValue(v, field.Index)
+
+		if i == len(path)-1 {
+			return t, field, v, basePath, true
+		}
+
+		t = field.Type
+		if t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+
+		if t.Kind() != reflect.Struct || t == timeType {
+			return nil, reflect.StructField{}, reflect.Value{}, "", false
+		}
+
+		v = structValue(v)
+		basePath += "/" + segment + "/properties"
+	}
+
+	return nil, reflect.StructField{}, reflect.Value{}, "", false
+}
+
+// fieldByJSONName returns the exported struct field with the given JSON name.
+func fieldByJSONName(t reflect.Type, name string) (reflect.StructField, bool) {
+	for i := range t.NumField() {
+		field := t.Field(i)
+		if field.IsExported() && fieldJSONName(field) == name {
+			return field, true
+		}
+	}
+
+	return reflect.StructField{}, false
+}
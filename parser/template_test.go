@@ -0,0 +1,163 @@
+package parser_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/holdemlab/ui-json-schema/parser"
+	"github.com/holdemlab/ui-json-schema/schema"
+)
+
+type TemplateAddress struct {
+	City   string `json:"city"`
+	Street string `json:"street"`
+}
+
+type TemplateForm struct {
+	Name    string          `json:"name" form:"label=Name;category=Main"`
+	Email   string          `json:"email" form:"layout=horizontal"`
+	Address TemplateAddress `json:"address"`
+	Secret  string          `json:"secret" form:"hidden"`
+	Notes   string          `json:"notes"`
+}
+
+func TestGenerateUISchema_Template(t *testing.T) {
+	opts := schema.DefaultOptions()
+	opts.Template = &schema.UISchemaTemplate{
+		Type: "VerticalLayout",
+		Elements: []*schema.UISchemaTemplate{
+			{Type: "HorizontalLayout", Elements: []*schema.UISchemaTemplate{
+				{Field: "address.city", Label: "Town"},
+				{Field: "email", Options: map[string]any{"placeholder": "you@example.com"}},
+			}},
+			{Field: "name"},
+			{Field: "secret"},
+		},
+	}
+
+	ui, err := parser.GenerateUISchemaWithOptions(TemplateForm{}, opts)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if ui.Type != "VerticalLayout" {
+		t.Fatalf("expected VerticalLayout, got %s", ui.Type)
+	}
+
+	// HorizontalLayout, name, then the remaining address.street and notes.
+	if len(ui.Elements) != 4 {
+		t.Fatalf("expected 4 elements, got %d", len(ui.Elements))
+	}
+
+	hl := ui.Elements[0]
+	if hl.Type != "HorizontalLayout" || len(hl.Elements) != 2 {
+		t.Fatalf("unexpected HorizontalLayout: %+v", hl)
+	}
+
+	city := hl.Elements[0]
+	if city.Scope != "#/properties/address/properties/city" || city.Label != "Town" {
+		t.Errorf("unexpected city control: %+v", city)
+	}
+
+	email := hl.Elements[1]
+	if email.Options["placeholder"] != "you@example.com" {
+		t.Errorf("expected template options on email, got %v", email.Options)
+	}
+
+	if _, ok := email.Options["layout"]; ok {
+		t.Error("expected layout hint to be stripped from templated field")
+	}
+
+	name := ui.Elements[1]
+	if name.Scope != "#/properties/name" || name.Label != "Name" || name.Options != nil {
+		t.Errorf("unexpected name control: %+v", name)
+	}
+
+	address := ui.Elements[2]
+	if address.Type != "Group" || len(address.Elements) != 1 ||
+		address.Elements[0].Scope != "#/properties/address/properties/street" {
+		t.Errorf("expected remaining address group with street only, got %+v", address)
+	}
+
+	if ui.Elements[3].Scope != "#/properties/notes" {
+		t.Errorf("expected remaining notes last, got %+v", ui.Elements[3])
+	}
+}
+
+func TestGenerateUISchema_TemplateCategorization(t *testing.T) {
+	opts := schema.DefaultOptions()
+	opts.Template = &schema.UISchemaTemplate{
+		Type: "Categorization",
+		Elements: []*schema.UISchemaTemplate{
+			{Type: "Category", Label: "Contact", Elements: []*schema.UISchemaTemplate{
+				{Field: "email"},
+				{Field: "address"},
+			}},
+		},
+	}
+
+	ui, err := parser.GenerateUISchemaWithOptions(TemplateForm{}, opts)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if ui.Type != "Categorization" || len(ui.Elements) != 2 {
+		t.Fatalf("expected Categorization with 2 categories, got %+v", ui)
+	}
+
+	contact := ui.Elements[0]
+	if contact.Label != "Contact" || len(contact.Elements) != 2 {
+		t.Fatalf("unexpected Contact category: %+v", contact)
+	}
+
+	if contact.Elements[1].Type != "Group" || len(contact.Elements[1].Elements) != 2 {
+		t.Errorf("expected address Group, got %+v", contact.Elements[1])
+	}
+
+	other := ui.Elements[1]
+	if other.Type != "Category" || other.Label != "Other" || len(other.Elements) != 2 {
+		t.Fatalf("expected remaining fields in Other, got %+v", other)
+	}
+
+	if other.Elements[0].Options != nil {
+		t.Errorf("expected category hint stripped from remaining field, got %v", other.Elements[0].Options)
+	}
+}
+
+func TestGenerateUISchema_TemplateUnknownField(t *testing.T) {
+	opts := schema.DefaultOptions()
+	opts.Template = &schema.UISchemaTemplate{
+		Elements: []*schema.UISchemaTemplate{{Field: "address.zip"}},
+	}
+
+	_, err := parser.GenerateUISchemaWithOptions(TemplateForm{}, opts)
+	if !errors.Is(err, parser.ErrUnknownTemplateField) {
+		t.Fatalf("expected ErrUnknownTemplateField, got %v", err)
+	}
+}
+
+func TestGenerateUISchema_TemplateRoleHidden(t *testing.T) {
+	opts := schema.DefaultOptions()
+	opts.Role = "guest"
+	opts.RolePermissions = map[string]schema.FieldPermissions{
+		"guest": {"notes": schema.AccessHidden},
+	}
+	opts.Template = &schema.UISchemaTemplate{
+		Elements: []*schema.UISchemaTemplate{{Field: "notes"}, {Field: "name"}},
+	}
+
+	ui, err := parser.GenerateUISchemaWithOptions(TemplateForm{}, opts)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, el := range ui.Elements {
+		if el.Scope == "#/properties/notes" {
+			t.Error("expected hidden field to be skipped")
+		}
+	}
+
+	if ui.Elements[0].Scope != "#/properties/name" {
+		t.Errorf("expected name first, got %+v", ui.Elements[0])
+	}
+}
@@ -0,0 +1,146 @@
+package parser
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/holdemlab/ui-json-schema/schema"
+)
+
+// rootValue returns the struct value passed to a Generate function.
+// A nil pointer yields the zero reflect.Value, which disables value-aware
+// generation (OmitEmpty, ValueDefaults) so only the type is inspected.
+func rootValue(v any) reflect.Value {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return reflect.Value{}
+		}
+
+		rv = rv.Elem()
+	}
+
+	return rv
+}
+
+// fieldValue returns the value of the struct field with the given index,
+// or the zero reflect.Value when the struct value is unknown.
+func fieldValue(v reflect.Value, index []int) reflect.Value {
+	if !v.IsValid() {
+		return reflect.Value{}
+	}
+
+	return v.FieldByIndex(index)
+}
+
+// structValue unwraps a nested struct field value. A nil pointer yields the
+// zero value of the struct, so its omitempty fields are treated as empty.
+func structValue(v reflect.Value) reflect.Value {
+	if !v.IsValid() || v.Kind() != reflect.Ptr {
+		return v
+	}
+
+	if v.IsNil() {
+		return reflect.Zero(v.Type().Elem())
+	}
+
+	return v.Elem()
+}
+
+// isOmittedField reports whether a field is dropped by Options.OmitEmpty:
+// as in encoding/json, it is tagged json:",omitempty" and its value is
+// empty, or json:",omitzero" and its value is zero. Fields with an unknown
+// value are never omitted.
+func isOmittedField(field reflect.StructField, v reflect.Value, opts *schema.Options) bool {
+	if opts == nil || !opts.OmitEmpty || !v.IsValid() {
+		return false
+	}
+
+	_, tagOpts, _ := strings.Cut(field.Tag.Get("json"), ",")
+	for _, opt := range strings.Split(tagOpts, ",") {
+		switch {
+		case opt == "omitempty" && isEmptyValue(v):
+			return true
+		case opt == "omitzero" && isZeroValue(v):
+			return true
+		}
+	}
+
+	return false
+}
+
+// isEmptyValue reports whether v is empty by the omitempty rules of
+// encoding/json: false, 0, a nil pointer or interface, or an empty array,
+// slice, map or string. Structs are never empty.
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() { //nolint:exhaustive // other kinds are never empty
+	case reflect.Slice, reflect.Map, reflect.String, reflect.Array:
+		return v.Len() == 0
+	case reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64, reflect.Interface, reflect.Ptr:
+		return v.IsZero()
+	default:
+		return false
+	}
+}
+
+// isZeroValue reports whether v is zero by the omitzero rules of
+// encoding/json: its IsZero method decides when it has one, e.g. for
+// time.Time, otherwise it is the zero value of its type.
+func isZeroValue(v reflect.Value) bool {
+	if (v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface) && v.IsNil() {
+		return true
+	}
+
+	if v.CanInterface() {
+		if z, ok := v.Interface().(interface{ IsZero() bool }); ok {
+			return z.IsZero()
+		}
+	}
+
+	return v.IsZero()
+}
+
+// applyValueDefault sets the JSON Schema default of a property to the
+// current field value when Options.ValueDefaults is enabled. Empty values
+// and nested structs (handled field by field) are skipped.
+func applyValueDefault(prop *schema.JSONSchema, v reflect.Value, opts *schema.Options) {
+	if opts == nil || !opts.ValueDefaults || !v.IsValid() || isEmptyValue(v) || v.IsZero() {
+		return
+	}
+
+	if v.Kind() == reflect.Ptr {
+		v = v.Elem()
+	}
+
+	if v.Kind() == reflect.Struct && v.Type() != timeType {
+		return
+	}
+
+	prop.Default = v.Interface()
+}
+
+// valueToSchema converts a field type to a JSON Schema property. Nested
+// structs with a known value are walked field by field so OmitEmpty and
+// ValueDefaults apply recursively; everything else is type-driven.
+func valueToSchema(t reflect.Type, v reflect.Value, opts *schema.Options) *schema.JSONSchema {
+	st := t
+	if st.Kind() == reflect.Ptr {
+		st = st.Elem()
+	}
+
+	if !v.IsValid() || st.Kind() != reflect.Struct || st == timeType {
+		return typeToSchema(t, opts)
+	}
+
+	obj := &schema.JSONSchema{
+		Type:       "object",
+		Properties: make(map[string]*schema.JSONSchema),
+		Nullable:   isNullable(t),
+	}
+	parseStructFields(st, structValue(v), obj, opts)
+
+	return obj
+}
@@ -0,0 +1,242 @@
+package parser_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/holdemlab/ui-json-schema/parser"
+	"github.com/holdemlab/ui-json-schema/schema"
+)
+
+type ArticleMeta struct {
+	Source string `json:"source,omitempty"`
+	Rating int    `json:"rating"`
+}
+
+type Article struct {
+	Title   string       `json:"title" required:"true"`
+	Content string       `json:"content"`
+	Notes   string       `json:"notes,omitempty" required:"true"`
+	Tags    []string     `json:"tags,omitempty"`
+	Views   int          `json:"views,omitempty" default:"10"`
+	Meta    ArticleMeta  `json:"meta"`
+	Extra   *ArticleMeta `json:"extra"`
+}
+
+func omitEmptyOptions() schema.Options {
+	opts := schema.DefaultOptions()
+	opts.OmitEmpty = true
+
+	return opts
+}
+
+func TestOmitEmpty_JSONSchema(t *testing.T) {
+	s, err := parser.GenerateJSONSchemaWithOptions(Article{Title: "Hello", Tags: []string{}}, omitEmptyOptions())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, name := range []string{"notes", "tags", "views"} {
+		if _, ok := s.Properties[name]; ok {
+			t.Errorf("expected %q to be omitted", name)
+		}
+	}
+
+	for _, name := range []string{"title", "content", "meta", "extra"} {
+		if _, ok := s.Properties[name]; !ok {
+			t.Errorf("expected %q to be kept", name)
+		}
+	}
+
+	if len(s.Required) != 1 || s.Required[0] != "title" {
+		t.Errorf("expected only title to be required, got %v", s.Required)
+	}
+
+	// Nested structs are filtered recursively, including nil pointers.
+	for _, name := range []string{"meta", "extra"} {
+		nested := s.Properties[name]
+		if _, ok := nested.Properties["source"]; ok {
+			t.Errorf("expected %s.source to be omitted", name)
+		}
+
+		if _, ok := nested.Properties["rating"]; !ok {
+			t.Errorf("expected %s.rating to be kept", name)
+		}
+	}
+}
+
+func TestOmitEmpty_Populated(t *testing.T) {
+	full := &Article{
+		Title: "Hello",
+		Notes: "draft",
+		Tags:  []string{"go"},
+		Views: 42,
+		Meta:  ArticleMeta{Source: "blog"},
+	}
+
+	s, err := parser.GenerateJSONSchemaWithOptions(full, omitEmptyOptions())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, name := range []string{"notes", "tags", "views"} {
+		if _, ok := s.Properties[name]; !ok {
+			t.Errorf("expected %q to be present", name)
+		}
+	}
+
+	if _, ok := s.Properties["meta"].Properties["source"]; !ok {
+		t.Error("expected meta.source to be present")
+	}
+}
+
+func TestOmitEmpty_Disabled(t *testing.T) {
+	s, err := parser.GenerateJSONSchema(Article{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(s.Properties) != 7 {
+		t.Errorf("expected all 7 properties without OmitEmpty, got %d", len(s.Properties))
+	}
+}
+
+func TestOmitEmpty_NilPointerIsTypeOnly(t *testing.T) {
+	s, err := parser.GenerateJSONSchemaWithOptions((*Article)(nil), omitEmptyOptions())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(s.Properties) != 7 {
+		t.Errorf("expected a nil instance to keep all 7 properties, got %d", len(s.Properties))
+	}
+}
+
+func TestOmitEmpty_UISchema(t *testing.T) {
+	ui, err := parser.GenerateUISchemaWithOptions(Article{Title: "Hello"}, omitEmptyOptions())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	scopes := make([]string, 0, len(ui.Elements))
+	for _, el := range ui.Elements {
+		scopes = append(scopes, el.Scope)
+	}
+
+	want := []string{"#/properties/title", "#/properties/content", "", ""}
+	if len(scopes) != len(want) {
+		t.Fatalf("expected %d elements, got %v", len(want), scopes)
+	}
+
+	for i := range want {
+		if scopes[i] != want[i] {
+			t.Errorf("element %d: expected scope %q, got %q", i, want[i], scopes[i])
+		}
+	}
+
+	meta := ui.Elements[2]
+	if len(meta.Elements) != 1 || meta.Elements[0].Scope != "#/properties/meta/properties/rating" {
+		t.Errorf("expected only meta.rating in Group, got %+v", meta.Elements)
+	}
+}
+
+func TestOmitEmpty_Template(t *testing.T) {
+	opts := omitEmptyOptions()
+	opts.Template = &schema.UISchemaTemplate{
+		Type: "VerticalLayout",
+		Elements: []*schema.UISchemaTemplate{
+			{Field: "notes"},
+			{Field: "title"},
+		},
+	}
+
+	ui, err := parser.GenerateUISchemaWithOptions(Article{Title: "Hello"}, opts)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if ui.Elements[0].Scope != "#/properties/title" {
+		t.Errorf("expected omitted notes to be skipped, got %q first", ui.Elements[0].Scope)
+	}
+
+	for _, el := range ui.Elements {
+		if el.Scope == "#/properties/tags" || el.Scope == "#/properties/views" {
+			t.Errorf("expected %s to be omitted from remaining fields", el.Scope)
+		}
+	}
+}
+
+func TestValueDefaults(t *testing.T) {
+	opts := schema.DefaultOptions()
+	opts.ValueDefaults = true
+
+	s, err := parser.GenerateJSONSchemaWithOptions(Article{
+		Title: "Hello",
+		Tags:  []string{"go"},
+		Extra: &ArticleMeta{Rating: 5},
+	}, opts)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if s.Properties["title"].Default != "Hello" {
+		t.Errorf("expected title default Hello, got %v", s.Properties["title"].Default)
+	}
+
+	if tags, ok := s.Properties["tags"].Default.([]string); !ok || len(tags) != 1 || tags[0] != "go" {
+		t.Errorf("expected tags default [go], got %v", s.Properties["tags"].Default)
+	}
+
+	// Empty values keep the tag default (or none).
+	if s.Properties["views"].Default != int64(10) {
+		t.Errorf("expected views tag default 10, got %#v", s.Properties["views"].Default)
+	}
+
+	if s.Properties["content"].Default != nil {
+		t.Errorf("expected no content default, got %v", s.Properties["content"].Default)
+	}
+
+	// Nested structs get defaults per field, not as a whole.
+	extra := s.Properties["extra"]
+	if extra.Default != nil {
+		t.Errorf("expected no object default, got %v", extra.Default)
+	}
+
+	if extra.Properties["rating"].Default != 5 {
+		t.Errorf("expected extra.rating default 5, got %v", extra.Properties["rating"].Default)
+	}
+}
+
+type omitEvent struct {
+	Name    string      `json:"name"`
+	Start   time.Time   `json:"start,omitempty"`
+	End     time.Time   `json:"end,omitzero"`
+	Meta    ArticleMeta `json:"meta,omitempty"`
+	Summary ArticleMeta `json:"summary,omitzero"`
+	Count   int         `json:"count,omitzero"`
+}
+
+func TestOmitEmpty_MatchesEncodingJSON(t *testing.T) {
+	s, err := parser.GenerateJSONSchemaWithOptions(omitEvent{}, omitEmptyOptions())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// omitempty never drops structs; omitzero drops zero values.
+	for name, kept := range map[string]bool{"start": true, "meta": true, "end": false, "summary": false, "count": false} {
+		if _, ok := s.Properties[name]; ok != kept {
+			t.Errorf("%s: present = %v, want %v", name, ok, kept)
+		}
+	}
+
+	s, err = parser.GenerateJSONSchemaWithOptions(omitEvent{End: time.Now(), Count: 1}, omitEmptyOptions())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, name := range []string{"end", "count"} {
+		if _, ok := s.Properties[name]; !ok {
+			t.Errorf("expected non-zero %q to be kept", name)
+		}
+	}
+}
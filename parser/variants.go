@@ -0,0 +1,233 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+
+	"github.com/holdemlab/ui-json-schema/schema"
+)
+
+// ErrNotInterface is returned when variants are registered for a type that
+// is not an interface.
+var ErrNotInterface = errors.New("variants require a pointer to an interface type")
+
+// ErrInvalidVariant is returned when a registered variant is malformed or
+// does not implement the interface.
+var ErrInvalidVariant = errors.New("invalid variant")
+
+// Variant describes one concrete implementation of a polymorphic interface.
+type Variant struct {
+	// Value is the discriminator value identifying the variant (e.g. "card").
+	Value string
+	// Type is an instance of the implementing struct (e.g. CardPayment{}).
+	Type any
+	// Label is the variant title in the schema and the UI; defaults to Value.
+	Label string
+}
+
+// variantSet holds the registered implementations of an interface type.
+type variantSet struct {
+	discriminator string
+	variants      []registeredVariant
+}
+
+// registeredVariant is a validated Variant with its struct type resolved.
+type registeredVariant struct {
+	value string
+	label string
+	typ   reflect.Type
+}
+
+// variantRegistry maps interface types to their registered variants.
+var variantRegistry = struct {
+	mu      sync.RWMutex
+	sets    map[reflect.Type]*variantSet
+	version uint64
+}{sets: make(map[reflect.Type]*variantSet)}
+
+// RegisterVariants registers the concrete implementations of an interface
+// type. iface is a nil pointer to the interface, e.g. (*PaymentMethod)(nil);
+// discriminator names the property holding the variant value. Fields of the
+// interface type then produce a oneOf JSON Schema and a UI variant selector.
+// Registering the same interface again replaces its variants.
+func RegisterVariants(iface any, discriminator string, variants ...Variant) error {
+	t := reflect.TypeOf(iface)
+	if t == nil || t.Kind() != reflect.Ptr || t.Elem().Kind() != reflect.Interface {
+		return fmt.Errorf("%w: %T", ErrNotInterface, iface)
+	}
+
+	t = t.Elem()
+
+	if discriminator == "" {
+		return fmt.Errorf("%w: empty discriminator for %s", ErrInvalidVariant, t)
+	}
+
+	set := &variantSet{discriminator: discriminator}
+	seen := make(map[string]bool, len(variants))
+
+	for _, v := range variants {
+		rv, err := resolveVariant(t, v)
+		if err != nil {
+			return err
+		}
+
+		if seen[rv.value] {
+			return fmt.Errorf("%w: duplicate value %q for %s", ErrInvalidVariant, rv.value, t)
+		}
+
+		seen[rv.value] = true
+		set.variants = append(set.variants, rv)
+	}
+
+	variantRegistry.mu.Lock()
+	defer variantRegistry.mu.Unlock()
+
+	variantRegistry.sets[t] = set
+	variantRegistry.version++
+
+	return nil
+}
+
+// VariantsRevision returns the number of successful RegisterVariants
+// calls. Caches of generated schemas include it in their keys, as
+// registering variants changes the schemas of the types using them.
+func VariantsRevision() uint64 {
+	variantRegistry.mu.RLock()
+	defer variantRegistry.mu.RUnlock()
+
+	return variantRegistry.version
+}
+
+// resolveVariant validates a variant against the interface type.
+func resolveVariant(iface reflect.Type, v Variant) (registeredVariant, error) {
+	if v.Value == "" {
+		return registeredVariant{}, fmt.Errorf("%w: empty value for %s", ErrInvalidVariant, iface)
+	}
+
+	impl := reflect.TypeOf(v.Type)
+	if impl == nil || !impl.Implements(iface) {
+		return registeredVariant{}, fmt.Errorf("%w: %q (%v) does not implement %s", ErrInvalidVariant, v.Value, impl, iface)
+	}
+
+	if impl.Kind() == reflect.Ptr {
+		impl = impl.Elem()
+	}
+
+	if impl.Kind() != reflect.Struct || impl == timeType {
+		return registeredVariant{}, fmt.Errorf("%w: %q (%v) is not a struct", ErrInvalidVariant, v.Value, impl)
+	}
+
+	label := v.Label
+	if label == "" {
+		label = v.Value
+	}
+
+	return registeredVariant{value: v.Value, label: label, typ: impl}, nil
+}
+
+// lookupVariants returns the variants registered for an interface type.
+func lookupVariants(t reflect.Type) (*variantSet, bool) {
+	if t.Kind() != reflect.Interface {
+		return nil, false
+	}
+
+	variantRegistry.mu.RLock()
+	defer variantRegistry.mu.RUnlock()
+
+	set, ok := variantRegistry.sets[t]
+
+	return set, ok
+}
+
+// interfaceToSchema converts an interface type to a JSON Schema property.
+// Registered interfaces become an object with a discriminator enum and one
+// oneOf branch per variant; unregistered ones accept any JSON value.
+// opts localizes the variant titles and fields; it may be nil.
+func interfaceToSchema(t reflect.Type, opts *schema.Options) *schema.JSONSchema {
+	set, ok := lookupVariants(t)
+	if !ok {
+		return &schema.JSONSchema{}
+	}
+
+	obj := &schema.JSONSchema{
+		Type: "object",
+		Properties: map[string]*schema.JSONSchema{
+			set.discriminator: set.discriminatorSchema(),
+		},
+		Required: []string{set.discriminator},
+	}
+
+	for _, v := range set.variants {
+		branch := typeToSchema(v.typ, opts)
+		branch.Title = translateLabel(v.label, "", opts)
+		branch.Properties[set.discriminator] = &schema.JSONSchema{Type: "string", Const: v.value}
+		branch.Required = append([]string{set.discriminator}, withoutName(branch.Required, set.discriminator)...)
+
+		obj.OneOf = append(obj.OneOf, branch)
+	}
+
+	return obj
+}
+
+// discriminatorSchema returns the string enum of all variant values.
+func (s *variantSet) discriminatorSchema() *schema.JSONSchema {
+	values := make([]any, 0, len(s.variants))
+	for _, v := range s.variants {
+		values = append(values, v.value)
+	}
+
+	return &schema.JSONSchema{Type: "string", Enum: values}
+}
+
+// withoutName returns names with every occurrence of name removed.
+func withoutName(names []string, name string) []string {
+	result := make([]string, 0, len(names))
+
+	for _, n := range names {
+		if n != name {
+			result = append(result, n)
+		}
+	}
+
+	return result
+}
+
+// buildVariantElements builds the UI Schema elements for a polymorphic
+// value at basePath: a Control selecting the variant by its discriminator,
+// followed by one Group per variant shown only while it is selected.
+func buildVariantElements(set *variantSet, basePath string, opts *schema.Options) []*schema.UISchemaElement {
+	selectorScope := basePath + "/" + set.discriminator
+
+	selector := schema.NewControl(selectorScope)
+	applyWidgetHints(selector, set.discriminatorSchema(), schema.FormOptions{}, opts)
+
+	elements := []*schema.UISchemaElement{selector}
+
+	for _, v := range set.variants {
+		group := schema.NewGroup(translateLabel(v.label, "", opts))
+		buildUIElements(v.typ, reflect.Value{}, basePath, group, opts)
+
+		// The discriminator is edited through the selector only.
+		kept := group.Elements[:0]
+		for _, el := range group.Elements {
+			if el.Scope != selectorScope {
+				kept = append(kept, el)
+			}
+		}
+
+		group.Elements = groupHorizontalElements(kept)
+		group.Rule = &schema.UISchemaRule{
+			Effect: schema.EffectShow,
+			Condition: &schema.UISchemaCondition{
+				Scope:  selectorScope,
+				Schema: &schema.JSONSchema{Const: v.value},
+			},
+		}
+
+		elements = append(elements, group)
+	}
+
+	return elements
+}
@@ -0,0 +1,186 @@
+package parser_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/holdemlab/ui-json-schema/parser"
+	"github.com/holdemlab/ui-json-schema/schema"
+)
+
+type PaymentMethod interface {
+	paymentMethod()
+}
+
+type CardPayment struct {
+	Kind   string `json:"kind"`
+	Number string `json:"number" required:"true"`
+	CVV    string `json:"cvv"`
+}
+
+func (CardPayment) paymentMethod() {}
+
+type BankPayment struct {
+	IBAN string `json:"iban" required:"true"`
+}
+
+func (*BankPayment) paymentMethod() {}
+
+type Checkout struct {
+	Amount   float64         `json:"amount"`
+	Payment  PaymentMethod   `json:"payment" form:"label=Payment method"`
+	Fallback []PaymentMethod `json:"fallback"`
+	Meta     any             `json:"meta"`
+}
+
+func registerPaymentVariants(t *testing.T) {
+	t.Helper()
+
+	err := parser.RegisterVariants((*PaymentMethod)(nil), "kind",
+		parser.Variant{Value: "card", Type: CardPayment{}, Label: "Card"},
+		parser.Variant{Value: "bank", Type: &BankPayment{}},
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestRegisterVariants_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		iface    any
+		disc     string
+		variants []parser.Variant
+		want     error
+	}{
+		{"not a pointer", PaymentMethod(nil), "kind", nil, parser.ErrNotInterface},
+		{"not an interface", (*CardPayment)(nil), "kind", nil, parser.ErrNotInterface},
+		{"empty discriminator", (*PaymentMethod)(nil), "", nil, parser.ErrInvalidVariant},
+		{"empty value", (*PaymentMethod)(nil), "kind", []parser.Variant{{Type: CardPayment{}}}, parser.ErrInvalidVariant},
+		{"not implemented", (*PaymentMethod)(nil), "kind", []parser.Variant{{Value: "bank", Type: BankPayment{}}}, parser.ErrInvalidVariant},
+		{"not a struct", (*any)(nil), "kind", []parser.Variant{{Value: "n", Type: 1}}, parser.ErrInvalidVariant},
+		{"duplicate value", (*PaymentMethod)(nil), "kind", []parser.Variant{
+			{Value: "card", Type: CardPayment{}},
+			{Value: "card", Type: &BankPayment{}},
+		}, parser.ErrInvalidVariant},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := parser.RegisterVariants(tt.iface, tt.disc, tt.variants...)
+			if !errors.Is(err, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestVariants_JSONSchema(t *testing.T) {
+	registerPaymentVariants(t)
+
+	s, err := parser.GenerateJSONSchema(Checkout{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	payment := s.Properties["payment"]
+	if payment.Type != "object" || len(payment.OneOf) != 2 {
+		t.Fatalf("expected object with 2 oneOf branches, got %+v", payment)
+	}
+
+	kind := payment.Properties["kind"]
+	if kind == nil || len(kind.Enum) != 2 || kind.Enum[0] != "card" || kind.Enum[1] != "bank" {
+		t.Errorf("expected kind enum [card bank], got %+v", kind)
+	}
+
+	if len(payment.Required) != 1 || payment.Required[0] != "kind" {
+		t.Errorf("expected kind to be required, got %v", payment.Required)
+	}
+
+	card := payment.OneOf[0]
+	if card.Title != "Card" || card.Properties["kind"].Const != "card" {
+		t.Errorf("expected Card branch with kind const, got %+v", card)
+	}
+
+	if _, ok := card.Properties["number"]; !ok {
+		t.Error("expected card branch to describe number")
+	}
+
+	if len(card.Required) != 2 || card.Required[0] != "kind" || card.Required[1] != "number" {
+		t.Errorf("expected required [kind number], got %v", card.Required)
+	}
+
+	bank := payment.OneOf[1]
+	if bank.Title != "bank" || bank.Properties["kind"].Const != "bank" {
+		t.Errorf("expected bank branch labelled by value, got %+v", bank)
+	}
+
+	if items := s.Properties["fallback"].Items; items == nil || len(items.OneOf) != 2 {
+		t.Errorf("expected polymorphic array items, got %+v", items)
+	}
+}
+
+func TestVariants_UnregisteredInterface(t *testing.T) {
+	s, err := parser.GenerateJSONSchema(Checkout{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	meta := s.Properties["meta"]
+	if meta.Type != "" || meta.Properties != nil || meta.OneOf != nil {
+		t.Errorf("expected empty schema for any, got %+v", meta)
+	}
+}
+
+func TestVariants_UISchema(t *testing.T) {
+	registerPaymentVariants(t)
+
+	ui, err := parser.GenerateUISchema(Checkout{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	group := ui.Elements[1]
+	if group.Type != "Group" || group.Label != "Payment method" || len(group.Elements) != 3 {
+		t.Fatalf("expected payment Group with selector and 2 variants, got %+v", group)
+	}
+
+	selector := group.Elements[0]
+	if selector.Scope != "#/properties/payment/properties/kind" {
+		t.Errorf("unexpected selector scope %q", selector.Scope)
+	}
+
+	if selector.Options["format"] != "radio" {
+		t.Errorf("expected radio selector, got %v", selector.Options)
+	}
+
+	card := group.Elements[1]
+	if card.Type != "Group" || card.Label != "Card" {
+		t.Fatalf("expected Card Group, got %+v", card)
+	}
+
+	if card.Rule == nil || card.Rule.Effect != schema.EffectShow ||
+		card.Rule.Condition.Scope != selector.Scope || card.Rule.Condition.Schema.Const != "card" {
+		t.Errorf("expected SHOW rule on kind=card, got %+v", card.Rule)
+	}
+
+	// The discriminator field of the variant struct is not duplicated.
+	if len(card.Elements) != 2 || card.Elements[0].Scope != "#/properties/payment/properties/number" {
+		t.Errorf("expected number and cvv controls, got %+v", card.Elements)
+	}
+
+	fallback := ui.Elements[2]
+
+	detail, ok := fallback.Options["detail"].(*schema.UISchemaElement)
+	if !ok || len(detail.Elements) != 3 {
+		t.Fatalf("expected variant detail for array items, got %v", fallback.Options)
+	}
+
+	if detail.Elements[0].Scope != "#/properties/kind" {
+		t.Errorf("expected item-relative selector scope, got %q", detail.Elements[0].Scope)
+	}
+
+	if meta := ui.Elements[3]; meta.Type != "Control" || meta.Scope != "#/properties/meta" {
+		t.Errorf("expected plain Control for unregistered interface, got %+v", meta)
+	}
+}
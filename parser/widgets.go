@@ -0,0 +1,147 @@
+package parser
+
+import "github.com/holdemlab/ui-json-schema/schema"
+
+// Widget names accepted by the form tag "widget=" key that produce options.
+// Any other value (checkbox, select, input, text, none) suppresses the
+// inferred widget so JSON Forms falls back to its default renderer.
+const (
+	widgetToggle = "toggle"
+	widgetRadio  = "radio"
+	widgetSlider = "slider"
+	widgetMulti  = "multi"
+	widgetFile   = "file"
+)
+
+// JSON Schema formats that map to date/time pickers.
+const (
+	formatDate     = "date"
+	formatTime     = "time"
+	formatDateTime = "date-time"
+)
+
+// Default widget inference thresholds (see schema.WidgetOptions).
+const (
+	defaultRadioMaxEnum       = 5
+	defaultMultilineMinLength = 255
+	defaultSliderSteps        = 100
+)
+
+// applyWidgetHints sets UI widget options on a primitive Control based on
+// the JSON Schema of its field. An explicit widget from the form tag
+// replaces the inferred one; "none" suppresses inference for the field.
+// Options already present on the control (e.g. passed through from the
+// form tag) are never overwritten.
+func applyWidgetHints(control *schema.UISchemaElement, prop *schema.JSONSchema, formOpts schema.FormOptions, opts *schema.Options) {
+	widget := formOpts.Widget
+	if widget == "" {
+		if opts != nil && opts.Widgets.Disabled {
+			return
+		}
+
+		widget = inferWidget(prop, opts)
+	}
+
+	switch widget {
+	case widgetToggle:
+		setDefaultOption(control, "toggle", true)
+	case widgetRadio:
+		setDefaultOption(control, "format", widgetRadio)
+	case widgetSlider:
+		setDefaultOption(control, "slider", true)
+
+		if step, ok := sliderStep(prop, opts); ok {
+			setDefaultOption(control, "step", step)
+		}
+	case widgetMulti:
+		setDefaultOption(control, "multi", true)
+	case widgetFile:
+		setDefaultOption(control, "format", widgetFile)
+	case formatDate, formatTime, formatDateTime:
+		setDefaultOption(control, "format", widget)
+	}
+}
+
+// setDefaultOption sets an option on the element unless it is already set.
+func setDefaultOption(el *schema.UISchemaElement, key string, value any) {
+	ensureOptions(el)
+
+	if _, exists := el.Options[key]; !exists {
+		el.Options[key] = value
+	}
+}
+
+// inferWidget picks a widget for a property from its JSON Schema facts.
+// Returns "" when no heuristic applies.
+func inferWidget(prop *schema.JSONSchema, opts *schema.Options) string {
+	switch {
+	case prop.ContentEncoding != "":
+		return widgetFile
+	case prop.Type == "boolean" && len(prop.Enum) == 0:
+		return widgetToggle
+	case prop.Format == formatDate || prop.Format == formatTime || prop.Format == formatDateTime:
+		return prop.Format
+	case len(prop.Enum) > 0 && len(prop.Enum) <= widgetThreshold(opts, radioMaxEnum):
+		return widgetRadio
+	case prop.Type == "string" && prop.MaxLength != nil && *prop.MaxLength > widgetThreshold(opts, multilineMinLength):
+		return widgetMulti
+	case (prop.Type == "integer" || prop.Type == "number") && prop.Minimum != nil && prop.Maximum != nil:
+		return widgetSlider
+	}
+
+	return ""
+}
+
+// sliderStep returns the step for a slider over the property's range.
+// Integer ranges step by 1; number ranges are divided into SliderSteps steps.
+func sliderStep(prop *schema.JSONSchema, opts *schema.Options) (any, bool) {
+	if prop.Minimum == nil || prop.Maximum == nil || *prop.Maximum <= *prop.Minimum {
+		return nil, false
+	}
+
+	if prop.Type == "integer" {
+		return 1, true
+	}
+
+	return (*prop.Maximum - *prop.Minimum) / float64(widgetThreshold(opts, sliderSteps)), true
+}
+
+// widgetSetting selects a threshold from schema.WidgetOptions.
+type widgetSetting int
+
+const (
+	radioMaxEnum widgetSetting = iota
+	multilineMinLength
+	sliderSteps
+)
+
+// widgetThreshold returns the configured threshold, or its default when unset.
+func widgetThreshold(opts *schema.Options, setting widgetSetting) int {
+	var w schema.WidgetOptions
+	if opts != nil {
+		w = opts.Widgets
+	}
+
+	switch setting {
+	case radioMaxEnum:
+		if w.RadioMaxEnum > 0 {
+			return w.RadioMaxEnum
+		}
+
+		return defaultRadioMaxEnum
+	case multilineMinLength:
+		if w.MultilineMinLength > 0 {
+			return w.MultilineMinLength
+		}
+
+		return defaultMultilineMinLength
+	case sliderSteps:
+		if w.SliderSteps > 0 {
+			return w.SliderSteps
+		}
+
+		return defaultSliderSteps
+	}
+
+	return 0
+}
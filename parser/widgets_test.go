@@ -0,0 +1,168 @@
+package parser_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/holdemlab/ui-json-schema/parser"
+	"github.com/holdemlab/ui-json-schema/schema"
+)
+
+type WidgetForm struct {
+	Active   bool      `json:"active"`
+	Birthday string    `json:"birthday" format:"date"`
+	Alarm    string    `json:"alarm" format:"time"`
+	Created  time.Time `json:"created"`
+	Size     string    `json:"size" enum:"S,M,L"`
+	Country  string    `json:"country" enum:"UA,PL,DE,FR,IT,ES"`
+	Bio      string    `json:"bio" maxLength:"1000"`
+	Nickname string    `json:"nickname" maxLength:"32"`
+	Volume   int       `json:"volume" minimum:"0" maximum:"10"`
+	Ratio    float64   `json:"ratio" minimum:"0" maximum:"1"`
+	Age      int       `json:"age" minimum:"0"`
+}
+
+type WidgetOverrideForm struct {
+	Active  bool   `json:"active" form:"widget=checkbox"`
+	Size    string `json:"size" enum:"S,M,L" form:"widget=select"`
+	Volume  int    `json:"volume" minimum:"0" maximum:"10" form:"widget=none"`
+	Comment string `json:"comment" form:"widget=multi"`
+	Level   int    `json:"level" enum:"1,2,3" form:"widget=slider"`
+}
+
+// widgetOptionsByScope generates a UI Schema and indexes control options by scope.
+func widgetOptionsByScope(t *testing.T, v any, opts schema.Options) map[string]map[string]any {
+	t.Helper()
+
+	ui, err := parser.GenerateUISchemaWithOptions(v, opts)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	result := make(map[string]map[string]any)
+	for _, el := range ui.Elements {
+		result[el.Scope] = el.Options
+	}
+
+	return result
+}
+
+func TestWidgets_Inferred(t *testing.T) {
+	byScope := widgetOptionsByScope(t, WidgetForm{}, schema.DefaultOptions())
+
+	tests := []struct {
+		scope string
+		key   string
+		want  any
+	}{
+		{"#/properties/active", "toggle", true},
+		{"#/properties/birthday", "format", "date"},
+		{"#/properties/alarm", "format", "time"},
+		{"#/properties/created", "format", "date-time"},
+		{"#/properties/size", "format", "radio"},
+		{"#/properties/bio", "multi", true},
+		{"#/properties/volume", "slider", true},
+		{"#/properties/volume", "step", 1},
+		{"#/properties/ratio", "slider", true},
+		{"#/properties/ratio", "step", 0.01},
+	}
+
+	for _, tt := range tests {
+		got := byScope[tt.scope][tt.key]
+		if got != tt.want {
+			t.Errorf("%s: expected options.%s=%v, got %v", tt.scope, tt.key, tt.want, got)
+		}
+	}
+
+	for _, scope := range []string{"#/properties/country", "#/properties/nickname", "#/properties/age"} {
+		if byScope[scope] != nil {
+			t.Errorf("%s: expected no inferred options, got %v", scope, byScope[scope])
+		}
+	}
+}
+
+func TestWidgets_TagOverrides(t *testing.T) {
+	byScope := widgetOptionsByScope(t, WidgetOverrideForm{}, schema.DefaultOptions())
+
+	for _, scope := range []string{"#/properties/active", "#/properties/size", "#/properties/volume"} {
+		if byScope[scope] != nil {
+			t.Errorf("%s: expected inference to be overridden, got %v", scope, byScope[scope])
+		}
+	}
+
+	if byScope["#/properties/comment"]["multi"] != true {
+		t.Errorf("expected explicit multi widget, got %v", byScope["#/properties/comment"])
+	}
+
+	level := byScope["#/properties/level"]
+	if level["slider"] != true {
+		t.Errorf("expected explicit slider widget, got %v", level)
+	}
+
+	if _, ok := level["step"]; ok {
+		t.Errorf("expected no step without minimum/maximum, got %v", level["step"])
+	}
+}
+
+func TestWidgets_Disabled(t *testing.T) {
+	opts := schema.DefaultOptions()
+	opts.Widgets.Disabled = true
+
+	byScope := widgetOptionsByScope(t, WidgetForm{}, opts)
+	for scope, o := range byScope {
+		if o != nil {
+			t.Errorf("%s: expected no options with inference disabled, got %v", scope, o)
+		}
+	}
+
+	// An explicit widget still applies when inference is disabled.
+	byScope = widgetOptionsByScope(t, WidgetOverrideForm{}, opts)
+	if byScope["#/properties/comment"]["multi"] != true {
+		t.Errorf("expected explicit multi widget, got %v", byScope["#/properties/comment"])
+	}
+}
+
+func TestWidgets_Thresholds(t *testing.T) {
+	opts := schema.DefaultOptions()
+	opts.Widgets = schema.WidgetOptions{
+		RadioMaxEnum:       6,
+		MultilineMinLength: 2000,
+		SliderSteps:        4,
+	}
+
+	byScope := widgetOptionsByScope(t, WidgetForm{}, opts)
+
+	if byScope["#/properties/country"]["format"] != "radio" {
+		t.Errorf("expected radio for 6-value enum, got %v", byScope["#/properties/country"])
+	}
+
+	if byScope["#/properties/bio"] != nil {
+		t.Errorf("expected no multi below threshold, got %v", byScope["#/properties/bio"])
+	}
+
+	if byScope["#/properties/ratio"]["step"] != 0.25 {
+		t.Errorf("expected step 0.25, got %v", byScope["#/properties/ratio"]["step"])
+	}
+}
+
+func TestWidgets_MultilineTagUnchanged(t *testing.T) {
+	type Form struct {
+		Notes string `json:"notes" form:"multiline;readonly"`
+	}
+
+	ui, err := parser.GenerateUISchema(Form{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	data, err := json.Marshal(ui.Elements[0])
+	if err != nil {
+		t.Fatalf("marshal error: %v", err)
+	}
+
+	want := `{"type":"Control","scope":"#/properties/notes","options":{"multi":true,"readonly":true}}`
+	if string(data) != want {
+		t.Errorf("expected %s, got %s", want, data)
+	}
+}
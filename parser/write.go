@@ -0,0 +1,172 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/holdemlab/ui-json-schema/schema"
+)
+
+// ErrForbiddenWrite is returned by CheckWrite when submitted data sets
+// fields that are read-only or hidden for the active roles.
+var ErrForbiddenWrite = errors.New("write to restricted fields")
+
+// ErrNotStruct is returned when a struct value is required.
+var ErrNotStruct = errors.New("value is not a struct")
+
+// FilterWrite enforces role permissions on submitted data for the struct
+// type of v: values of fields that are AccessReadOnly or AccessHidden for
+// the active roles in opts are dropped, resolved as for schema generation
+// (RolePermissions, RoleInherits, RolePolicy and access tags). It returns a
+// filtered copy of data and the JSON Pointers of the dropped values in
+// sorted order; data itself is not modified. A collection whose items or
+// values are restricted is dropped as a whole. Keys match properties as in
+// encoding/json: exactly, else case-insensitively, so "Salary" is checked
+// as "salary". Keys matching no property are kept.
+func FilterWrite(v any, data map[string]any, opts schema.Options) (map[string]any, []string, error) {
+	t := reflect.TypeOf(v)
+	if t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil, nil, fmt.Errorf("%w: %T", ErrNotStruct, v)
+	}
+
+	// Permissions are matched against the full schema of the type.
+	s, _ := fullJSONSchema(v, &opts)
+
+	w := writeFilter{rules: newAccessRules(t, &opts)}
+	filtered, _ := w.filter(s, data, "", "").(map[string]any)
+	sort.Strings(w.removed)
+
+	return filtered, w.removed, nil
+}
+
+// CheckWrite is FilterWrite that rejects instead of dropping: it returns
+// an error wrapping ErrForbiddenWrite and listing the JSON Pointers of the
+// restricted values, or nil when data writes only permitted fields.
+func CheckWrite(v any, data map[string]any, opts schema.Options) error {
+	_, removed, err := FilterWrite(v, data, opts)
+	if err != nil {
+		return err
+	}
+
+	if len(removed) > 0 {
+		return fmt.Errorf("%w: %s", ErrForbiddenWrite, strings.Join(removed, ", "))
+	}
+
+	return nil
+}
+
+// writeFilter collects the values dropped by one FilterWrite call.
+type writeFilter struct {
+	rules   *accessRules
+	removed []string
+}
+
+// restricted reports whether the field at path may not be written.
+func (w *writeFilter) restricted(path string) bool {
+	return w.rules != nil && w.rules.level(path) != schema.AccessReadWrite
+}
+
+// filter returns a copy of value without restricted values. path is the
+// permission path of value and pointer its JSON Pointer.
+func (w *writeFilter) filter(s *schema.JSONSchema, value any, path, pointer string) any {
+	if s == nil {
+		return value
+	}
+
+	switch val := value.(type) {
+	case map[string]any:
+		return w.filterObject(s, val, path, pointer)
+	case []any:
+		items := make([]any, len(val))
+		for i, item := range val {
+			items[i] = w.filter(s.Items, item, path+pathItems, pointer+"/"+strconv.Itoa(i))
+		}
+
+		return items
+	default:
+		return value
+	}
+}
+
+// filterObject filters the properties of a struct object, or the values
+// of a map object, dropping restricted ones.
+func (w *writeFilter) filterObject(s *schema.JSONSchema, obj map[string]any, path, pointer string) map[string]any {
+	result := make(map[string]any, len(obj))
+
+	for key, value := range obj {
+		ptr := pointer + "/" + schema.EscapePointer(key)
+
+		name, prop := propertySchema(s, key)
+		fieldPath := joinPath(path, name)
+
+		if prop == nil && s.AdditionalProperties != nil {
+			prop, fieldPath = s.AdditionalProperties, path+pathItems
+		}
+
+		if prop == nil {
+			result[key] = value
+			continue
+		}
+
+		if w.restricted(fieldPath) || w.restrictedItems(prop, value, fieldPath) {
+			w.removed = append(w.removed, ptr)
+			continue
+		}
+
+		result[key] = w.filter(prop, value, fieldPath, ptr)
+	}
+
+	return result
+}
+
+// restrictedItems reports whether value is a collection whose items or
+// map values are restricted, so it cannot be written as a whole.
+func (w *writeFilter) restrictedItems(s *schema.JSONSchema, value any, path string) bool {
+	switch value.(type) {
+	case []any:
+		return s.Items != nil && w.restricted(path+pathItems)
+	case map[string]any:
+		return s.AdditionalProperties != nil && w.restricted(path+pathItems)
+	default:
+		return false
+	}
+}
+
+// propertySchema returns the declared name and schema of the property of
+// s matching key, looking in the oneOf branches of polymorphic fields as
+// well. Like encoding/json, an exact match wins over a case-insensitive
+// one.
+func propertySchema(s *schema.JSONSchema, key string) (string, *schema.JSONSchema) {
+	schemas := append([]*schema.JSONSchema{s}, s.OneOf...)
+
+	for _, branch := range schemas {
+		if prop, ok := branch.Properties[key]; ok {
+			return key, prop
+		}
+	}
+
+	for _, branch := range schemas {
+		names := make([]string, 0, len(branch.Properties))
+		for name := range branch.Properties {
+			names = append(names, name)
+		}
+
+		sort.Strings(names)
+
+		for _, name := range names {
+			if strings.EqualFold(name, key) {
+				return name, branch.Properties[name]
+			}
+		}
+	}
+
+	return "", nil
+}
@@ -0,0 +1,170 @@
+package parser_test
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/holdemlab/ui-json-schema/parser"
+	"github.com/holdemlab/ui-json-schema/schema"
+)
+
+type WriteLine struct {
+	SKU   string  `json:"sku"`
+	Price float64 `json:"price"`
+}
+
+type WriteOrder struct {
+	ID     int               `json:"id"`
+	Status string            `json:"status" access:"admin=rw;*=ro"`
+	Note   string            `json:"note"`
+	Lines  []WriteLine       `json:"lines"`
+	Tags   []string          `json:"tags"`
+	Meta   map[string]string `json:"meta"`
+}
+
+func writeOptions(perms schema.FieldPermissions) schema.Options {
+	opts := schema.DefaultOptions()
+	opts.Role = "clerk"
+	opts.RolePermissions = map[string]schema.FieldPermissions{"clerk": perms}
+
+	return opts
+}
+
+func TestFilterWrite(t *testing.T) {
+	data := map[string]any{
+		"id":     float64(1),
+		"status": "paid",
+		"note":   "hi",
+		"lines": []any{
+			map[string]any{"sku": "a", "price": float64(5)},
+			map[string]any{"sku": "b"},
+		},
+		"tags":  []any{"x"},
+		"meta":  map[string]any{"k": "v"},
+		"extra": true,
+	}
+
+	filtered, removed, err := parser.FilterWrite(WriteOrder{}, data, writeOptions(schema.FieldPermissions{
+		"id":            schema.AccessHidden,
+		"lines[].price": schema.AccessReadOnly,
+		"tags[]":        schema.AccessReadOnly,
+		"meta[]":        schema.AccessHidden,
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := map[string]any{
+		"note": "hi",
+		"lines": []any{
+			map[string]any{"sku": "a"},
+			map[string]any{"sku": "b"},
+		},
+		"extra": true,
+	}
+	if !reflect.DeepEqual(filtered, expected) {
+		t.Errorf("got %v, want %v", filtered, expected)
+	}
+
+	expectedRemoved := []string{"/id", "/lines/0/price", "/meta", "/status", "/tags"}
+	if !reflect.DeepEqual(removed, expectedRemoved) {
+		t.Errorf("removed %v, want %v", removed, expectedRemoved)
+	}
+
+	if _, ok := data["id"]; !ok {
+		t.Error("expected the submitted data to be left unmodified")
+	}
+}
+
+func TestFilterWrite_PermittedRole(t *testing.T) {
+	opts := writeOptions(nil)
+	opts.Role = "admin"
+
+	data := map[string]any{"status": "paid", "meta": map[string]any{"k": "v"}}
+
+	filtered, removed, err := parser.FilterWrite(&WriteOrder{}, data, opts)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !reflect.DeepEqual(filtered, data) || removed != nil {
+		t.Errorf("expected data unchanged for admin, got %v, removed %v", filtered, removed)
+	}
+
+	// Without a role only the "*" tag entries apply.
+	if _, removed, _ := parser.FilterWrite(WriteOrder{}, data, schema.DefaultOptions()); !reflect.DeepEqual(removed, []string{"/status"}) {
+		t.Errorf("expected /status removed without a role, got %v", removed)
+	}
+}
+
+func TestFilterWrite_EscapesPointers(t *testing.T) {
+	type Doc struct {
+		Props map[string]WriteLine `json:"props"`
+	}
+
+	_, removed, err := parser.FilterWrite(Doc{}, map[string]any{
+		"props": map[string]any{"a~/b": map[string]any{"price": float64(1), "sku": "s"}},
+	}, writeOptions(schema.FieldPermissions{"props[].price": schema.AccessHidden}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !reflect.DeepEqual(removed, []string{"/props/a~0~1b/price"}) {
+		t.Errorf("unexpected removed %v", removed)
+	}
+}
+
+func TestCheckWrite(t *testing.T) {
+	opts := writeOptions(schema.FieldPermissions{"note": schema.AccessReadOnly})
+
+	if err := parser.CheckWrite(WriteOrder{}, map[string]any{"id": float64(2)}, opts); err != nil {
+		t.Errorf("expected permitted write, got %v", err)
+	}
+
+	err := parser.CheckWrite(WriteOrder{}, map[string]any{"note": "x", "status": "new"}, opts)
+	if !errors.Is(err, parser.ErrForbiddenWrite) || !strings.Contains(err.Error(), "/note, /status") {
+		t.Errorf("expected ErrForbiddenWrite listing /note and /status, got %v", err)
+	}
+
+	if err := parser.CheckWrite("text", nil, opts); !errors.Is(err, parser.ErrNotStruct) {
+		t.Errorf("expected ErrNotStruct, got %v", err)
+	}
+}
+
+func TestCheckWrite_AnyRoleWithoutRole(t *testing.T) {
+	// Status is tagged "admin=rw;*=ro": a caller without a role is "*".
+	data := map[string]any{"status": "paid", "note": "hi"}
+
+	filtered, removed, err := parser.FilterWrite(WriteOrder{}, data, schema.DefaultOptions())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, ok := filtered["status"]; ok || !reflect.DeepEqual(removed, []string{"/status"}) {
+		t.Errorf("expected /status dropped without a role, got %v, removed %v", filtered, removed)
+	}
+
+	err = parser.CheckWrite(WriteOrder{}, data, schema.DefaultOptions())
+	if !errors.Is(err, parser.ErrForbiddenWrite) || !strings.Contains(err.Error(), "/status") {
+		t.Errorf("expected ErrForbiddenWrite listing /status, got %v", err)
+	}
+}
+
+func TestFilterWrite_MatchesKeysCaseInsensitively(t *testing.T) {
+	data := map[string]any{"STATUS": "paid", "Note": "hi", "Extra": true}
+
+	filtered, removed, err := parser.FilterWrite(WriteOrder{}, data, writeOptions(nil))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !reflect.DeepEqual(removed, []string{"/STATUS"}) {
+		t.Errorf("expected STATUS to be checked as status, removed %v", removed)
+	}
+
+	if !reflect.DeepEqual(filtered, map[string]any{"Note": "hi", "Extra": true}) {
+		t.Errorf("unexpected filtered data %v", filtered)
+	}
+}
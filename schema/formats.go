@@ -0,0 +1,110 @@
+package schema
+
+import (
+	"net"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// formatCheckers validates strings by JSON Schema format name.
+var formatCheckers = map[string]func(string) bool{
+	"date-time": isDateTime,
+	"date":      isDate,
+	"time":      isTime,
+	"email":     isEmail,
+	"hostname":  isHostname,
+	"ipv4":      isIPv4,
+	"ipv6":      isIPv6,
+	"uri":       isURI,
+	"uuid":      uuidPattern.MatchString,
+	"regex":     isRegex,
+}
+
+// uuidPattern matches a hyphenated UUID (RFC 4122).
+var uuidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// hostnameLabel matches one DNS label.
+var hostnameLabel = regexp.MustCompile(`^[a-zA-Z0-9]([a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?$`)
+
+// ValidFormat reports whether s is valid for the JSON Schema format:
+// date-time, date, time, email, hostname, ipv4, ipv6, uri, uuid or regex.
+// Other formats are not checked and always valid.
+func ValidFormat(format, s string) bool {
+	check, ok := formatCheckers[format]
+
+	return !ok || check(s)
+}
+
+// parses reports whether s parses with the time layout.
+func parses(layout, s string) bool {
+	_, err := time.Parse(layout, s)
+
+	return err == nil
+}
+
+// isDateTime validates an RFC 3339 date-time, accepting a lowercase "t"
+// or "z" as RFC 3339 allows.
+func isDateTime(s string) bool {
+	return parses(time.RFC3339Nano, strings.ToUpper(s))
+}
+
+// isDate validates an RFC 3339 full-date such as "2024-01-31".
+func isDate(s string) bool {
+	return parses(time.DateOnly, s)
+}
+
+// isTime validates an RFC 3339 full-time such as "14:30:00Z".
+func isTime(s string) bool {
+	return parses("15:04:05.999999999Z07:00", strings.ToUpper(s))
+}
+
+// isEmail validates a bare address such as "a@example.com", rejecting
+// display names and angle brackets.
+func isEmail(s string) bool {
+	addr, err := mail.ParseAddress(s)
+
+	return err == nil && addr.Address == s && addr.Name == ""
+}
+
+// isHostname validates a DNS hostname (RFC 1123).
+func isHostname(s string) bool {
+	s = strings.TrimSuffix(s, ".")
+	if s == "" || len(s) > 253 {
+		return false
+	}
+
+	for _, label := range strings.Split(s, ".") {
+		if !hostnameLabel.MatchString(label) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// isURI validates an absolute URI with a scheme.
+func isURI(s string) bool {
+	u, err := url.Parse(s)
+
+	return err == nil && u.Scheme != "" && !strings.ContainsAny(s, " \t\n")
+}
+
+// isIPv4 validates a dotted-quad IPv4 address.
+func isIPv4(s string) bool {
+	return !strings.Contains(s, ":") && net.ParseIP(s) != nil
+}
+
+// isIPv6 validates an IPv6 address.
+func isIPv6(s string) bool {
+	return strings.Contains(s, ":") && net.ParseIP(s) != nil
+}
+
+// isRegex validates a regular expression.
+func isRegex(s string) bool {
+	_, err := regexp.Compile(s)
+
+	return err == nil
+}
@@ -1,5 +1,10 @@
 package schema
 
+import (
+	"sort"
+	"strings"
+)
+
 // Translator resolves localized strings by key and locale.
 // Implementations may load translations from files, databases or
 // embedded maps.
@@ -9,6 +14,35 @@ type Translator interface {
 	Translate(key, locale string) string
 }
 
+// ParamTranslator is implemented by Translators that format messages with
+// named parameters ("At least {min} characters") and select plural forms by
+// the PluralParam parameter. The generator passes field constraints as
+// parameters (see FieldTags.Params); plain Translators keep working through
+// TranslateParams.
+type ParamTranslator interface {
+	Translator
+	// TranslateParams returns the localized, formatted message for key. If
+	// no translation is found, it should return the key formatted.
+	TranslateParams(key, locale string, params map[string]any) string
+}
+
+// TranslateParams translates key with tr and formats the result with
+// params, using tr's own formatting when it is a ParamTranslator.
+func TranslateParams(tr Translator, key, locale string, params map[string]any) string {
+	if pt, ok := tr.(ParamTranslator); ok {
+		return pt.TranslateParams(key, locale, params)
+	}
+
+	return FormatMessage(tr.Translate(key, locale), params)
+}
+
+// LocaleLister is implemented by Translators that can list the locales
+// they hold translations for (see parser.GenerateLocalized).
+type LocaleLister interface {
+	// Locales returns the available locales in a stable order.
+	Locales() []string
+}
+
 // MapTranslator is a simple in-memory Translator backed by nested maps.
 // The outer map key is the locale (e.g. "uk", "en"), the inner key is
 // the translation key, and the value is the translated string.
@@ -25,6 +59,18 @@ func NewMapTranslator(m map[string]map[string]string) *MapTranslator {
 	return &MapTranslator{translations: m}
 }
 
+// Locales returns the locales of the translator, sorted.
+func (t *MapTranslator) Locales() []string {
+	locales := make([]string, 0, len(t.translations))
+	for locale := range t.translations {
+		locales = append(locales, locale)
+	}
+
+	sort.Strings(locales)
+
+	return locales
+}
+
 // Translate returns the translation for the given key and locale.
 // Falls back to the key itself when no translation exists.
 func (t *MapTranslator) Translate(key, locale string) string {
@@ -36,3 +82,101 @@ func (t *MapTranslator) Translate(key, locale string) string {
 
 	return key
 }
+
+// TranslateParams returns the translation for key formatted with params.
+// With a PluralParam parameter the form "<key>#<category>" for its plural
+// category is preferred (see LoadTranslator), e.g. "files#few" in "uk"
+// for a count of 3.
+func (t *MapTranslator) TranslateParams(key, locale string, params map[string]any) string {
+	msg := t.Translate(key, locale)
+
+	if n, ok := params[PluralParam]; ok {
+		formKey := key + "#" + PluralCategory(locale, n)
+		if form := t.Translate(formKey, locale); form != formKey {
+			msg = form
+		}
+	}
+
+	return FormatMessage(msg, params)
+}
+
+// FallbackTranslator resolves a key through a locale fallback chain:
+// "uk-UA" tries "uk-UA", then "uk", then the default locale.
+type FallbackTranslator struct {
+	base          Translator
+	defaultLocale string
+}
+
+// NewFallbackTranslator wraps base with locale fallback. defaultLocale is
+// the last locale tried (e.g. "en"); it may be empty.
+func NewFallbackTranslator(base Translator, defaultLocale string) *FallbackTranslator {
+	return &FallbackTranslator{base: base, defaultLocale: defaultLocale}
+}
+
+// Translate returns the first translation found along the fallback chain
+// of locale, or the key unchanged.
+func (t *FallbackTranslator) Translate(key, locale string) string {
+	for _, l := range FallbackChain(locale, t.defaultLocale) {
+		if val := t.base.Translate(key, l); val != key {
+			return val
+		}
+	}
+
+	return key
+}
+
+// TranslateParams formats the message of the first locale along the
+// fallback chain that translates key, or the key itself.
+func (t *FallbackTranslator) TranslateParams(key, locale string, params map[string]any) string {
+	for _, l := range FallbackChain(locale, t.defaultLocale) {
+		if t.base.Translate(key, l) != key {
+			return TranslateParams(t.base, key, l, params)
+		}
+	}
+
+	return FormatMessage(key, params)
+}
+
+// Locales returns the locales of the wrapped translator when it is a
+// LocaleLister.
+func (t *FallbackTranslator) Locales() []string {
+	if lister, ok := t.base.(LocaleLister); ok {
+		return lister.Locales()
+	}
+
+	return nil
+}
+
+// FallbackChain returns the locales tried for locale: the locale itself,
+// its parents obtained by dropping "-" or "_" subtags, then defaultLocale.
+// Duplicates and empty locales are omitted.
+func FallbackChain(locale, defaultLocale string) []string {
+	var chain []string
+
+	add := func(l string) {
+		for _, c := range chain {
+			if c == l {
+				return
+			}
+		}
+
+		if l != "" {
+			chain = append(chain, l)
+		}
+	}
+
+	for l := locale; l != ""; {
+		add(l)
+
+		i := strings.LastIndexAny(l, "-_")
+		if i < 0 {
+			break
+		}
+
+		l = l[:i]
+	}
+
+	add(defaultLocale)
+
+	return chain
+}
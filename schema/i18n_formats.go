@@ -0,0 +1,730 @@
+package schema
+
+import (
+	"bufio"
+	"bytes"
+	"cmp"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// The parsers in this file cover the subset of YAML, TOML and gettext PO
+// used by translation catalogues: nested string maps, go-i18n plural
+// tables and msgid/msgstr entries. Anything else is reported as
+// ErrTranslationFormat instead of being guessed.
+
+// yamlFrame is an open mapping while parsing YAML by indentation. child is
+// the indentation of its keys, or -1 until the first one is read.
+type yamlFrame struct {
+	indent int
+	child  int
+	node   map[string]any
+}
+
+// parseYAMLTree parses block mappings of strings, including quoted scalars
+// and literal (|) or folded (>) block scalars. Tab or uneven indentation,
+// duplicate keys and any other YAML construct are rejected.
+func parseYAMLTree(data []byte) (map[string]any, error) {
+	root := make(map[string]any)
+	stack := []yamlFrame{{indent: -1, child: -1, node: root}}
+	lines := strings.Split(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n")
+	started := false
+
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		trimmed := strings.TrimSpace(line)
+
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+
+		// A document marker may only open the file.
+		if trimmed == "---" && !started {
+			started = true
+			continue
+		}
+
+		started = true
+		indent := len(line) - len(strings.TrimLeft(line, " "))
+
+		if line[indent] == '\t' {
+			return nil, fmt.Errorf("%w: line %d: tab indentation", ErrTranslationFormat, i+1)
+		}
+
+		key, rest, ok := splitYAMLKey(trimmed)
+		if !ok {
+			return nil, fmt.Errorf("%w: line %d: expected \"key: value\"", ErrTranslationFormat, i+1)
+		}
+
+		for len(stack) > 1 && stack[len(stack)-1].indent >= indent {
+			stack = stack[:len(stack)-1]
+		}
+
+		frame := &stack[len(stack)-1]
+		if frame.child < 0 {
+			frame.child = indent
+		}
+
+		if indent != frame.child {
+			return nil, fmt.Errorf("%w: line %d: inconsistent indentation", ErrTranslationFormat, i+1)
+		}
+
+		parent := frame.node
+		if _, dup := parent[key]; dup {
+			return nil, fmt.Errorf("%w: line %d: duplicate key %q", ErrTranslationFormat, i+1, key)
+		}
+
+		value := stripYAMLComment(rest)
+
+		switch {
+		case value == "":
+			child := make(map[string]any)
+			parent[key] = child
+			stack = append(stack, yamlFrame{indent: indent, child: -1, node: child})
+		case value == "|" || value == ">":
+			var block []string
+
+			block, i = yamlBlock(lines, i+1, indent)
+			if value == "|" {
+				parent[key] = strings.Join(block, "\n")
+			} else {
+				parent[key] = strings.Join(block, " ")
+			}
+		default:
+			scalar, err := parseYAMLScalar(value)
+			if err != nil {
+				return nil, fmt.Errorf("%w: line %d: %w", ErrTranslationFormat, i+1, err)
+			}
+
+			parent[key] = scalar
+		}
+	}
+
+	return root, nil
+}
+
+// splitYAMLKey splits "key: value" honoring quoted keys.
+func splitYAMLKey(line string) (key, rest string, ok bool) {
+	if line[0] == '"' || line[0] == '\'' {
+		end := strings.IndexByte(line[1:], line[0])
+		if end < 0 || !strings.HasPrefix(line[end+2:], ":") {
+			return "", "", false
+		}
+
+		return line[1 : end+1], strings.TrimSpace(line[end+3:]), true
+	}
+
+	if yamlIndicator(line) {
+		return "", "", false
+	}
+
+	key, rest, ok = strings.Cut(line, ":")
+	if !ok || (rest != "" && rest[0] != ' ') {
+		return "", "", false
+	}
+
+	key = strings.TrimSpace(key)
+
+	return key, strings.TrimSpace(rest), key != ""
+}
+
+// yamlIndicator reports whether s starts a YAML construct other than a
+// plain scalar: a sequence, flow collection, anchor, alias, tag, block
+// scalar, directive or reserved character.
+func yamlIndicator(s string) bool {
+	switch s[0] {
+	case '-', '?', ':':
+		return len(s) == 1 || s[1] == ' '
+	case ',', '[', ']', '{', '}', '#', '&', '*', '!', '|', '>', '%', '@', '`':
+		return true
+	}
+
+	return false
+}
+
+// stripYAMLComment removes a trailing " #" comment outside quotes.
+func stripYAMLComment(value string) string {
+	if value == "" || value[0] == '"' || value[0] == '\'' {
+		return value
+	}
+
+	if value[0] == '#' {
+		return ""
+	}
+
+	if i := strings.Index(value, " #"); i >= 0 {
+		return strings.TrimSpace(value[:i])
+	}
+
+	return value
+}
+
+// parseYAMLScalar unquotes a YAML scalar, allowing a trailing comment after
+// a quoted one. Plain scalars are kept verbatim.
+func parseYAMLScalar(value string) (string, error) {
+	switch value[0] {
+	case '"':
+		end := closingQuote(value)
+		if end < 0 {
+			return "", fmt.Errorf("unterminated string %s", value)
+		}
+
+		if err := checkTrailing(value[end+1:]); err != nil {
+			return "", err
+		}
+
+		return strconv.Unquote(value[:end+1])
+	case '\'':
+		end := closingSingleQuote(value)
+		if end < 0 {
+			return "", fmt.Errorf("unterminated string %s", value)
+		}
+
+		if err := checkTrailing(value[end+1:]); err != nil {
+			return "", err
+		}
+
+		return strings.ReplaceAll(value[1:end], "''", "'"), nil
+	}
+
+	if yamlIndicator(value) || strings.Contains(value, ": ") {
+		return "", fmt.Errorf("unsupported value %s", value)
+	}
+
+	return value, nil
+}
+
+// closingSingleQuote returns the index of the quote closing a single-quoted
+// YAML string, where a doubled quote stands for one quote.
+func closingSingleQuote(s string) int {
+	for i := 1; i < len(s); i++ {
+		if s[i] != '\'' {
+			continue
+		}
+
+		if i+1 < len(s) && s[i+1] == '\'' {
+			i++
+			continue
+		}
+
+		return i
+	}
+
+	return -1
+}
+
+// checkTrailing rejects anything but a comment after a quoted string.
+func checkTrailing(rest string) error {
+	rest = strings.TrimSpace(rest)
+	if rest == "" || strings.HasPrefix(rest, "#") {
+		return nil
+	}
+
+	return fmt.Errorf("unexpected %s after string", rest)
+}
+
+// yamlBlock collects the lines of a block scalar more indented than the
+// key at parentIndent. It returns the lines and the index of the last one.
+func yamlBlock(lines []string, start, parentIndent int) ([]string, int) {
+	var block []string
+
+	blockIndent := -1
+	last := start - 1
+
+	for i := start; i < len(lines); i++ {
+		line := lines[i]
+		if strings.TrimSpace(line) == "" {
+			block = append(block, "")
+			continue
+		}
+
+		indent := len(line) - len(strings.TrimLeft(line, " "))
+		if indent <= parentIndent {
+			break
+		}
+
+		if blockIndent < 0 {
+			blockIndent = indent
+		}
+
+		block = append(block, line[min(blockIndent, indent):])
+		last = i
+	}
+
+	return block[:max(0, len(block)-trailingEmpty(block))], last
+}
+
+// trailingEmpty counts the empty lines at the end of a block.
+func trailingEmpty(block []string) int {
+	n := 0
+	for i := len(block) - 1; i >= 0 && block[i] == ""; i-- {
+		n++
+	}
+
+	return n
+}
+
+// parseTOMLTree parses tables ([a.b] or ["a.b"]) of key = "string" pairs,
+// including multi-line basic strings, as used by go-i18n message files.
+// Duplicate keys or tables, arrays, inline tables and non-string values
+// are rejected.
+func parseTOMLTree(data []byte) (map[string]any, error) {
+	root := make(map[string]any)
+	current := root
+	defined := make(map[string]bool)
+	lines := strings.Split(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n")
+
+	for i := 0; i < len(lines); i++ {
+		line := strings.TrimSpace(lines[i])
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		var err error
+
+		if strings.HasPrefix(line, "[") {
+			current, err = tomlHeader(root, defined, line)
+		} else {
+			i, err = tomlPair(current, lines, i)
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("%w: line %d: %w", ErrTranslationFormat, i+1, err)
+		}
+	}
+
+	return root, nil
+}
+
+// tomlHeader opens the table of a "[a.b]" header line. Each table may be
+// declared once.
+func tomlHeader(root map[string]any, defined map[string]bool, line string) (map[string]any, error) {
+	if strings.HasPrefix(line, "[[") {
+		return nil, errors.New("arrays of tables are not supported")
+	}
+
+	header, rest, ok := cutTOML(line[1:], ']')
+	if !ok {
+		return nil, errors.New("unterminated table header")
+	}
+
+	if err := checkTrailing(rest); err != nil {
+		return nil, err
+	}
+
+	path, err := splitTOMLKey(header)
+	if err != nil {
+		return nil, err
+	}
+
+	id := strings.Join(path, "\x00")
+	if defined[id] {
+		return nil, fmt.Errorf("table %s declared twice", strings.TrimSpace(header))
+	}
+
+	defined[id] = true
+
+	return tomlTable(root, path)
+}
+
+// tomlPair stores the key = value pair starting at lines[i] in table and
+// returns the index of its last line.
+func tomlPair(table map[string]any, lines []string, i int) (int, error) {
+	rawKey, value, ok := cutTOML(strings.TrimSpace(lines[i]), '=')
+	if !ok {
+		return i, errors.New("expected key = value")
+	}
+
+	path, err := splitTOMLKey(rawKey)
+	if err != nil {
+		return i, err
+	}
+
+	var text string
+
+	value = strings.TrimSpace(value)
+	if strings.HasPrefix(value, `"""`) {
+		text, i, err = tomlMultiline(lines, i, value)
+	} else {
+		text, err = parseTOMLString(value)
+	}
+
+	if err != nil {
+		return i, err
+	}
+
+	parent, err := tomlTable(table, path[:len(path)-1])
+	if err != nil {
+		return i, err
+	}
+
+	key := path[len(path)-1]
+	if _, dup := parent[key]; dup {
+		return i, fmt.Errorf("duplicate key %s", key)
+	}
+
+	parent[key] = text
+
+	return i, nil
+}
+
+// cutTOML slices s around the first sep outside quotes.
+func cutTOML(s string, sep byte) (before, after string, found bool) {
+	var quote byte
+
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == sep:
+			return s[:i], s[i+1:], true
+		}
+	}
+
+	return s, "", false
+}
+
+// splitTOMLKey splits a dotted TOML key, honoring quoted segments.
+func splitTOMLKey(key string) ([]string, error) {
+	var parts []string
+
+	rest := strings.TrimSpace(key)
+
+	for {
+		var part string
+
+		if rest != "" && (rest[0] == '"' || rest[0] == '\'') {
+			end := strings.IndexByte(rest[1:], rest[0])
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated key %s", rest)
+			}
+
+			part, rest = rest[1:end+1], strings.TrimSpace(rest[end+2:])
+		} else {
+			i := strings.IndexByte(rest, '.')
+			if i < 0 {
+				i = len(rest)
+			}
+
+			part, rest = strings.TrimSpace(rest[:i]), rest[i:]
+			if !isBareKey(part) {
+				return nil, fmt.Errorf("invalid key %q", strings.TrimSpace(key))
+			}
+		}
+
+		parts = append(parts, part)
+
+		if rest == "" {
+			return parts, nil
+		}
+
+		if rest[0] != '.' {
+			return nil, fmt.Errorf("invalid key %q", strings.TrimSpace(key))
+		}
+
+		rest = strings.TrimSpace(rest[1:])
+	}
+}
+
+// isBareKey reports whether s is a valid unquoted TOML key.
+func isBareKey(s string) bool {
+	if s == "" {
+		return false
+	}
+
+	for _, c := range s {
+		if (c < 'a' || c > 'z') && (c < 'A' || c > 'Z') && (c < '0' || c > '9') && c != '_' && c != '-' {
+			return false
+		}
+	}
+
+	return true
+}
+
+// tomlTable returns the nested table at path, creating it when needed. It
+// fails when a key on the path already holds a string.
+func tomlTable(root map[string]any, path []string) (map[string]any, error) {
+	node := root
+
+	for _, p := range path {
+		v, exists := node[p]
+
+		child, ok := v.(map[string]any)
+		if exists && !ok {
+			return nil, fmt.Errorf("key %s is not a table", p)
+		}
+
+		if !exists {
+			child = make(map[string]any)
+			node[p] = child
+		}
+
+		node = child
+	}
+
+	return node, nil
+}
+
+// tomlMultiline reads a multi-line basic string starting at lines[start]
+// and returns its text with the index of its last line. Escapes, including
+// line ending backslashes, are not supported.
+func tomlMultiline(lines []string, start int, first string) (string, int, error) {
+	var parts []string
+
+	body := strings.TrimPrefix(first, `"""`)
+
+	for i := start; i < len(lines); i++ {
+		if i > start {
+			body = lines[i]
+		}
+
+		text, rest, closed := strings.Cut(body, `"""`)
+		if strings.Contains(text, `\`) {
+			return "", i, errors.New("escapes in multi-line strings are not supported")
+		}
+
+		// The newline right after the opening quotes is trimmed.
+		if i > start || text != "" {
+			parts = append(parts, text)
+		}
+
+		if closed {
+			return strings.Join(parts, "\n"), i, checkTrailing(rest)
+		}
+	}
+
+	return "", start, errors.New("unterminated multi-line string")
+}
+
+// parseTOMLString unquotes a basic ("...") or literal ('...') string,
+// allowing a trailing comment.
+func parseTOMLString(value string) (string, error) {
+	if value == "" {
+		return "", errors.New("missing value")
+	}
+
+	switch value[0] {
+	case '"':
+		end := closingQuote(value)
+		if end < 0 {
+			return "", fmt.Errorf("unterminated string %s", value)
+		}
+
+		if err := checkTrailing(value[end+1:]); err != nil {
+			return "", err
+		}
+
+		return strconv.Unquote(value[:end+1])
+	case '\'':
+		end := strings.IndexByte(value[1:], '\'')
+		if end < 0 {
+			return "", fmt.Errorf("unterminated string %s", value)
+		}
+
+		if err := checkTrailing(value[end+2:]); err != nil {
+			return "", err
+		}
+
+		return value[1 : end+1], nil
+	}
+
+	return "", fmt.Errorf("unsupported value %s", value)
+}
+
+// closingQuote returns the index of the quote closing a basic string.
+func closingQuote(s string) int {
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '"':
+			return i
+		}
+	}
+
+	return -1
+}
+
+// poEntry accumulates one gettext catalogue entry.
+type poEntry struct {
+	msgctxt string
+	msgid   string
+	msgstr  map[int]string
+	field   string
+	index   int
+	plural  bool
+	fuzzy   bool
+}
+
+// key returns the message key of the entry: the msgid, prefixed with
+// "<msgctxt>." when the entry has a context.
+func (e *poEntry) key() string {
+	if e.field == "" || e.msgctxt == "" && e.msgid == "" {
+		return ""
+	}
+
+	if e.msgctxt != "" {
+		return e.msgctxt + "." + e.msgid
+	}
+
+	return e.msgid
+}
+
+// parsePO parses a gettext .po catalogue. The header entry (empty msgid)
+// provides the locale through its "Language:" line, else fileLocale is
+// used. Untranslated and fuzzy ("#, fuzzy") entries are skipped. An entry
+// with a msgctxt is keyed "<msgctxt>.<msgid>", so equal msgids in
+// different contexts stay apart. Plural entries use msgstr[0] and keep
+// every msgstr[N] as "<key>#<category>" in the CLDR order of the locale
+// (uk: one, few, many).
+func parsePO(data []byte, fileLocale string) (string, map[string]string, error) {
+	messages := make(map[string]string)
+	locale := ""
+	entry := &poEntry{msgstr: map[int]string{}}
+
+	var plurals []*poEntry
+
+	flush := func() {
+		switch key := entry.key(); {
+		case entry.field == "":
+		case key == "":
+			locale = poHeaderLanguage(entry.msgstr[0])
+		case entry.fuzzy || entry.msgstr[0] == "":
+		default:
+			messages[key] = entry.msgstr[0]
+
+			if entry.plural {
+				plurals = append(plurals, entry)
+			}
+		}
+
+		entry = &poEntry{msgstr: map[int]string{}}
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	lineNo := 0
+
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+
+		if line == "" {
+			continue
+		}
+
+		// Comments open the next entry; "#," lines hold its flags.
+		if strings.HasPrefix(line, "#") {
+			if entry.field == "msgstr" {
+				flush()
+			}
+
+			if flags, ok := strings.CutPrefix(line, "#,"); ok && poHasFlag(flags, "fuzzy") {
+				entry.fuzzy = true
+			}
+
+			continue
+		}
+
+		if strings.HasPrefix(line, `"`) {
+			text, err := strconv.Unquote(line)
+			if err != nil {
+				return "", nil, fmt.Errorf("%w: line %d: %w", ErrTranslationFormat, lineNo, err)
+			}
+
+			entry.append(text)
+
+			continue
+		}
+
+		keyword, value, _ := strings.Cut(line, " ")
+
+		text, err := strconv.Unquote(strings.TrimSpace(value))
+		if err != nil {
+			return "", nil, fmt.Errorf("%w: line %d: %w", ErrTranslationFormat, lineNo, err)
+		}
+
+		switch {
+		case keyword == "msgctxt", keyword == "msgid":
+			if entry.field == "msgstr" {
+				flush()
+			}
+
+			entry.field = keyword
+			entry.append(text)
+		case keyword == "msgid_plural":
+			entry.field = keyword
+			entry.plural = true
+		case keyword == "msgstr":
+			entry.field, entry.index = "msgstr", 0
+			entry.append(text)
+		case strings.HasPrefix(keyword, "msgstr["):
+			n, err := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(keyword, "msgstr["), "]"))
+			if err != nil {
+				return "", nil, fmt.Errorf("%w: line %d: %w", ErrTranslationFormat, lineNo, err)
+			}
+
+			entry.field, entry.index = "msgstr", n
+			entry.append(text)
+		default:
+			return "", nil, fmt.Errorf("%w: line %d: unknown keyword %q", ErrTranslationFormat, lineNo, keyword)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return "", nil, err
+	}
+
+	flush()
+
+	forms := pluralForms(cmp.Or(locale, fileLocale))
+
+	for _, e := range plurals {
+		for i, text := range e.msgstr {
+			if i < len(forms) && text != "" {
+				messages[e.key()+"#"+forms[i]] = text
+			}
+		}
+	}
+
+	return locale, messages, nil
+}
+
+// append adds a string (or continuation line) to the current field.
+func (e *poEntry) append(text string) {
+	switch e.field {
+	case "msgctxt":
+		e.msgctxt += text
+	case "msgid":
+		e.msgid += text
+	case "msgstr":
+		e.msgstr[e.index] += text
+	}
+}
+
+// poHasFlag reports whether a comma-separated "#," flag list holds flag.
+func poHasFlag(flags, flag string) bool {
+	for _, f := range strings.Split(flags, ",") {
+		if strings.TrimSpace(f) == flag {
+			return true
+		}
+	}
+
+	return false
+}
+
+// poHeaderLanguage extracts the "Language:" value from a PO header.
+func poHeaderLanguage(header string) string {
+	for _, line := range strings.Split(header, "\n") {
+		if name, value, ok := strings.Cut(line, ":"); ok && strings.TrimSpace(name) == "Language" {
+			return strings.TrimSpace(value)
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,216 @@
+package schema
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"strings"
+)
+
+// ErrTranslationFormat is returned when a translation file cannot be parsed.
+var ErrTranslationFormat = errors.New("invalid translation file")
+
+// pluralCategories lists the CLDR plural categories. go-i18n messages store
+// their forms under these keys; loaders keep them as "<key>#<category>".
+var pluralCategories = []string{PluralZero, PluralOne, PluralTwo, PluralFew, PluralMany, PluralOther}
+
+// goI18nMetaKeys lists the non-plural keys allowed in a go-i18n message.
+var goI18nMetaKeys = map[string]bool{
+	"id": true, "description": true, "hash": true, "leftDelim": true, "rightDelim": true,
+}
+
+// LoadTranslator builds a MapTranslator from the translation files in dir
+// of fsys (use "." for the root, e.g. of an embed.FS). Supported files:
+//
+//   - <locale>.json, <locale>.yaml, <locale>.yml — nested objects flattened
+//     with dots ({"Order": {"name": {"label": "Ім'я"}}} → "Order.name.label")
+//   - <name>.<locale>.toml/json/yaml — go-i18n message files; plural
+//     messages use their "other" form, other forms are kept as "<key>#one"
+//   - *.po — gettext catalogues; the locale is read from the "Language"
+//     header, else from the file name; plural msgstr[N] are kept as
+//     "<key>#<category>"
+//
+// Files for the same locale are merged in directory order; other files
+// are ignored.
+func LoadTranslator(fsys fs.FS, dir string) (*MapTranslator, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	translations := make(map[string]map[string]string)
+
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+
+		name := entry.Name()
+		ext := path.Ext(name)
+
+		parse, ok := translationParsers[ext]
+		if !ok {
+			continue
+		}
+
+		data, err := fs.ReadFile(fsys, path.Join(dir, name))
+		if err != nil {
+			return nil, err
+		}
+
+		fileLocale := localeFromFileName(name)
+
+		locale, messages, err := parse(data, fileLocale)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+
+		if locale == "" {
+			locale = fileLocale
+		}
+
+		if translations[locale] == nil {
+			translations[locale] = make(map[string]string)
+		}
+
+		for k, v := range messages {
+			translations[locale][k] = v
+		}
+	}
+
+	return NewMapTranslator(translations), nil
+}
+
+// LoadTranslatorDir builds a MapTranslator from the translation files in a
+// directory of the OS file system (see LoadTranslator).
+func LoadTranslatorDir(dir string) (*MapTranslator, error) {
+	return LoadTranslator(os.DirFS(dir), ".")
+}
+
+// translationParser parses a translation file into flat messages.
+// fileLocale is the locale derived from the file name; a non-empty
+// returned locale overrides it.
+type translationParser func(data []byte, fileLocale string) (locale string, messages map[string]string, err error)
+
+// translationParsers maps file extensions to their parsers.
+var translationParsers = map[string]translationParser{
+	".json": treeParser(parseJSONTree),
+	".yaml": treeParser(parseYAMLTree),
+	".yml":  treeParser(parseYAMLTree),
+	".toml": treeParser(parseTOMLTree),
+	".po":   parsePO,
+}
+
+// treeParser adapts a parser producing a nested tree to a translationParser.
+func treeParser(parse func(data []byte) (map[string]any, error)) translationParser {
+	return func(data []byte, _ string) (string, map[string]string, error) {
+		tree, err := parse(data)
+		if err != nil {
+			return "", nil, err
+		}
+
+		messages := make(map[string]string)
+		if err := flattenMessages("", tree, messages); err != nil {
+			return "", nil, err
+		}
+
+		return "", messages, nil
+	}
+}
+
+// localeFromFileName returns the last dotted segment before the extension:
+// "uk.json" → "uk", "active.uk-UA.toml" → "uk-UA".
+func localeFromFileName(name string) string {
+	base := strings.TrimSuffix(name, path.Ext(name))
+	if i := strings.LastIndexByte(base, '.'); i >= 0 {
+		return base[i+1:]
+	}
+
+	return base
+}
+
+// parseJSONTree decodes a JSON object.
+func parseJSONTree(data []byte) (map[string]any, error) {
+	var tree map[string]any
+	if err := json.Unmarshal(data, &tree); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrTranslationFormat, err)
+	}
+
+	return tree, nil
+}
+
+// flattenMessages flattens a nested message tree into dotted keys.
+// go-i18n plural messages ({"one": ..., "other": ...}) are kept as a
+// single key with "#<category>" variants.
+func flattenMessages(prefix string, tree map[string]any, out map[string]string) error {
+	for k, v := range tree {
+		key := k
+		if prefix != "" {
+			key = prefix + "." + k
+		}
+
+		switch val := v.(type) {
+		case string:
+			out[key] = val
+		case map[string]any:
+			if isPluralMessage(val) {
+				addPluralMessage(key, val, out)
+				continue
+			}
+
+			if err := flattenMessages(key, val, out); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("%w: %q has unsupported value %v", ErrTranslationFormat, key, v)
+		}
+	}
+
+	return nil
+}
+
+// isPluralMessage reports whether a node is a go-i18n message: it has an
+// "other" form and only plural or metadata keys.
+func isPluralMessage(node map[string]any) bool {
+	if _, ok := node[PluralOther].(string); !ok {
+		return false
+	}
+
+	for k := range node {
+		if !goI18nMetaKeys[k] && !isPluralCategory(k) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// isPluralCategory reports whether name is a CLDR plural category.
+func isPluralCategory(name string) bool {
+	for _, c := range pluralCategories {
+		if c == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// addPluralMessage stores a go-i18n message: the "other" form under key
+// and every form under "<key>#<category>".
+func addPluralMessage(key string, node map[string]any, out map[string]string) {
+	if id, ok := node["id"].(string); ok && id != "" {
+		key = id
+	}
+
+	for _, c := range pluralCategories {
+		if form, ok := node[c].(string); ok {
+			out[key+"#"+c] = form
+		}
+	}
+
+	out[key] = out[key+"#"+PluralOther]
+}
@@ -0,0 +1,294 @@
+package schema_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"testing/fstest"
+
+	"github.com/holdemlab/ui-json-schema/schema"
+)
+
+func TestLoadTranslator_Formats(t *testing.T) {
+	fsys := fstest.MapFS{
+		"i18n/uk.json": {Data: []byte(`{"Order": {"name": {"label": "Ім'я"}}, "save": "Зберегти"}`)},
+		"i18n/en.yaml": {Data: []byte(`# English
+Order:
+  name:
+    label: Name   # inline comment
+    description: "Customer \"full\" name"
+  note: |
+    Line one
+    Line two
+"a.b": 'It''s'
+`)},
+		"i18n/active.de.toml": {Data: []byte(`
+save = "Speichern"
+
+[Order]
+title = 'Bestellung'
+
+[Items]
+description = "Anzahl"
+one = "{{.Count}} Artikel"
+other = """
+{{.Count}} Artikel
+insgesamt"""
+`)},
+		"i18n/messages.po": {Data: []byte(`# Ukrainian (Ukraine)
+msgid ""
+msgstr ""
+"Language: uk-UA\n"
+"Content-Type: text/plain; charset=UTF-8\n"
+
+#: order.go:12
+msgid "Order.name.label"
+msgstr "Ім'я "
+"клієнта"
+
+msgid "Untranslated"
+msgstr ""
+
+msgid "item"
+msgid_plural "items"
+msgstr[0] "товар"
+msgstr[1] "товари"
+msgstr[2] "товарів"
+`)},
+		"i18n/README.md":     {Data: []byte("ignored")},
+		"i18n/nested/x.json": {Data: []byte("{}")},
+	}
+
+	tr, err := schema.LoadTranslator(fsys, "i18n")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := tr.Locales(); !reflect.DeepEqual(got, []string{"de", "en", "uk", "uk-UA"}) {
+		t.Errorf("unexpected locales %v", got)
+	}
+
+	tests := []struct {
+		key, locale, expected string
+	}{
+		{"Order.name.label", "uk", "Ім'я"},
+		{"save", "uk", "Зберегти"},
+		{"Order.name.label", "en", "Name"},
+		{"Order.name.description", "en", `Customer "full" name`},
+		{"Order.note", "en", "Line one\nLine two"},
+		{"a.b", "en", "It's"},
+		{"save", "de", "Speichern"},
+		{"Order.title", "de", "Bestellung"},
+		{"Items", "de", "{{.Count}} Artikel\ninsgesamt"},
+		{"Items#one", "de", "{{.Count}} Artikel"},
+		{"Order.name.label", "uk-UA", "Ім'я клієнта"},
+		{"item", "uk-UA", "товар"},
+		{"item#few", "uk-UA", "товари"},
+		{"item#many", "uk-UA", "товарів"},
+		{"Untranslated", "uk-UA", "Untranslated"},
+	}
+
+	for _, tt := range tests {
+		if got := tr.Translate(tt.key, tt.locale); got != tt.expected {
+			t.Errorf("Translate(%q, %q) = %q, want %q", tt.key, tt.locale, got, tt.expected)
+		}
+	}
+}
+
+func TestLoadTranslator_MergesLocaleFiles(t *testing.T) {
+	fsys := fstest.MapFS{
+		"active.uk.json": {Data: []byte(`{"PersonCats": {"one": "{{.Count}} кіт", "few": "{{.Count}} коти", "other": "{{.Count}} котів"}}`)},
+		"uk.yml":         {Data: []byte("save: Зберегти\n")},
+	}
+
+	tr, err := schema.LoadTranslator(fsys, ".")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := tr.Translate("save", "uk"); got != "Зберегти" {
+		t.Errorf("expected merged yaml message, got %q", got)
+	}
+
+	if got := tr.Translate("PersonCats#few", "uk"); got != "{{.Count}} коти" {
+		t.Errorf("expected plural form, got %q", got)
+	}
+
+	if got := tr.Translate("PersonCats", "uk"); got != "{{.Count}} котів" {
+		t.Errorf("expected other form as default, got %q", got)
+	}
+}
+
+func TestLoadTranslator_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		file string
+		data string
+	}{
+		{"json syntax", "uk.json", `{"a": `},
+		{"json number", "uk.json", `{"a": 1}`},
+		{"yaml list", "uk.yaml", "items:\n  - one\n"},
+		{"yaml flow", "uk.yaml", "a: [1, 2]\n"},
+		{"toml array table", "uk.toml", "[[a]]\n"},
+		{"toml number", "uk.toml", "a = 1\n"},
+		{"po keyword", "uk.po", "msgfoo \"x\"\n"},
+		{"po quote", "uk.po", "msgid \"x\nmsgstr \"y\"\n"},
+		{"yaml tab indent", "uk.yaml", "a:\n\tb: c\n"},
+		{"yaml uneven indent", "uk.yaml", "a:\n    b: c\n  d: e\n"},
+		{"yaml duplicate key", "uk.yaml", "a: b\na: c\n"},
+		{"yaml chomping indicator", "uk.yaml", "a: |-\n  b\n"},
+		{"yaml anchor", "uk.yaml", "a: &x b\n"},
+		{"yaml second document", "uk.yaml", "a: b\n---\nc: d\n"},
+		{"yaml trailing text", "uk.yaml", "a: \"b\" c\n"},
+		{"toml trailing text", "uk.toml", "a = \"b\" c\n"},
+		{"toml unterminated multiline", "uk.toml", "a = \"\"\"\nb\n"},
+		{"toml multiline escape", "uk.toml", "a = \"\"\"\nb\\\n\"\"\"\n"},
+		{"toml duplicate key", "uk.toml", "a = \"b\"\na = \"c\"\n"},
+		{"toml duplicate table", "uk.toml", "[a]\nb = \"c\"\n[a]\nd = \"e\"\n"},
+		{"toml string as table", "uk.toml", "a = \"b\"\n[a]\n"},
+		{"toml invalid key", "uk.toml", "a b = \"c\"\n"},
+		{"toml inline table", "uk.toml", "a = { b = \"c\" }\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fsys := fstest.MapFS{tt.file: {Data: []byte(tt.data)}}
+
+			if _, err := schema.LoadTranslator(fsys, "."); !errors.Is(err, schema.ErrTranslationFormat) {
+				t.Errorf("expected ErrTranslationFormat, got %v", err)
+			}
+		})
+	}
+
+	if _, err := schema.LoadTranslator(fstest.MapFS{}, "missing"); err == nil {
+		t.Error("expected error for missing directory")
+	}
+}
+
+func TestLoadTranslatorDir(t *testing.T) {
+	tr, err := schema.LoadTranslatorDir(t.TempDir())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(tr.Locales()) != 0 {
+		t.Errorf("expected no locales, got %v", tr.Locales())
+	}
+}
+
+func TestFallbackChain(t *testing.T) {
+	tests := []struct {
+		locale, def string
+		expected    []string
+	}{
+		{"uk-UA", "en", []string{"uk-UA", "uk", "en"}},
+		{"zh_Hant_TW", "en", []string{"zh_Hant_TW", "zh_Hant", "zh", "en"}},
+		{"en-US", "en", []string{"en-US", "en"}},
+		{"", "en", []string{"en"}},
+		{"uk", "", []string{"uk"}},
+	}
+
+	for _, tt := range tests {
+		if got := schema.FallbackChain(tt.locale, tt.def); !reflect.DeepEqual(got, tt.expected) {
+			t.Errorf("FallbackChain(%q, %q) = %v, want %v", tt.locale, tt.def, got, tt.expected)
+		}
+	}
+}
+
+func TestFallbackTranslator(t *testing.T) {
+	base := schema.NewMapTranslator(map[string]map[string]string{
+		"uk-UA": {"color": "Колір (UA)"},
+		"uk":    {"color": "Колір", "name": "Ім'я"},
+		"en":    {"color": "Color", "name": "Name", "save": "Save"},
+	})
+	tr := schema.NewFallbackTranslator(base, "en")
+
+	tests := []struct {
+		key, locale, expected string
+	}{
+		{"color", "uk-UA", "Колір (UA)"},
+		{"name", "uk-UA", "Ім'я"},
+		{"save", "uk-UA", "Save"},
+		{"save", "fr", "Save"},
+		{"missing", "uk-UA", "missing"},
+	}
+
+	for _, tt := range tests {
+		if got := tr.Translate(tt.key, tt.locale); got != tt.expected {
+			t.Errorf("Translate(%q, %q) = %q, want %q", tt.key, tt.locale, got, tt.expected)
+		}
+	}
+
+	if got := tr.Locales(); !reflect.DeepEqual(got, []string{"en", "uk", "uk-UA"}) {
+		t.Errorf("expected base locales, got %v", got)
+	}
+}
+
+func TestLoadTranslator_POPluralsByFileLocale(t *testing.T) {
+	fsys := fstest.MapFS{
+		"en.po": {Data: []byte(`msgid "file"
+msgid_plural "files"
+msgstr[0] "{count} file"
+msgstr[1] "{count} files"
+`)},
+	}
+
+	tr, err := schema.LoadTranslator(fsys, ".")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := tr.TranslateParams("file", "en", map[string]any{"count": 5}); got != "5 files" {
+		t.Errorf("expected other form, got %q", got)
+	}
+}
+
+func TestLoadTranslator_POFuzzyAndContext(t *testing.T) {
+	fsys := fstest.MapFS{
+		"uk.po": {Data: []byte(`msgid ""
+msgstr "Language: uk\n"
+
+#, fuzzy
+msgid "save"
+msgstr "Зберехти"
+
+# translator comment
+#, c-format, fuzzy
+msgid "cancel"
+msgstr "Скасувати"
+
+msgctxt "Order"
+msgid "name"
+msgstr "Назва замовлення"
+
+msgctxt "Customer"
+msgid "name"
+msgstr "Ім'я клієнта"
+
+msgid "name"
+msgstr "Ім'я"
+`)},
+	}
+
+	tr, err := schema.LoadTranslator(fsys, ".")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		key  string
+		want string
+	}{
+		{"save", "save"},
+		{"cancel", "cancel"},
+		{"Order.name", "Назва замовлення"},
+		{"Customer.name", "Ім'я клієнта"},
+		{"name", "Ім'я"},
+	}
+
+	for _, tt := range tests {
+		if got := tr.Translate(tt.key, "uk"); got != tt.want {
+			t.Errorf("Translate(%q) = %q, want %q", tt.key, got, tt.want)
+		}
+	}
+}
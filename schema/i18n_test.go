@@ -83,3 +83,26 @@ func TestDefaultOptions(t *testing.T) {
 		t.Errorf("expected draft-07 URL, got %q", opts.DraftURL())
 	}
 }
+
+func TestMapTranslator_Locales(t *testing.T) {
+	tr := schema.NewMapTranslator(map[string]map[string]string{
+		"uk": {},
+		"en": {},
+		"de": {},
+	})
+
+	var lister schema.LocaleLister = tr
+
+	got := lister.Locales()
+	want := []string{"de", "en", "uk"}
+
+	if len(got) != len(want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("expected %v, got %v", want, got)
+		}
+	}
+}
@@ -8,9 +8,13 @@ type JSONSchema struct {
 	Type                 string                 `json:"type,omitempty"`
 	Properties           map[string]*JSONSchema `json:"properties,omitempty"`
 	Items                *JSONSchema            `json:"items,omitempty"`
+	MinItems             *int                   `json:"minItems,omitempty"`
+	MaxItems             *int                   `json:"maxItems,omitempty"`
 	AdditionalProperties *JSONSchema            `json:"additionalProperties,omitempty"`
+	PropertyNames        *JSONSchema            `json:"propertyNames,omitempty"`
 	Required             []string               `json:"required,omitempty"`
 	Format               string                 `json:"format,omitempty"`
+	ContentEncoding      string                 `json:"contentEncoding,omitempty"`
 	Default              any                    `json:"default,omitempty"`
 	Enum                 []any                  `json:"enum,omitempty"`
 	Description          string                 `json:"description,omitempty"`
@@ -21,6 +25,16 @@ type JSONSchema struct {
 	Minimum              *float64               `json:"minimum,omitempty"`
 	Maximum              *float64               `json:"maximum,omitempty"`
 	Pattern              string                 `json:"pattern,omitempty"`
+	ReadOnly             bool                   `json:"readOnly,omitempty"`
+	OneOf                []*JSONSchema          `json:"oneOf,omitempty"`
+	// ErrorMessage holds custom validation messages (ajv-errors): keyword →
+	// message, "_" for all other errors, and on objects "required" →
+	// property → message.
+	ErrorMessage map[string]any `json:"errorMessage,omitempty"`
+	// Nullable makes Validate accept null besides Type, as encoding/json
+	// writes for nil pointers, slices, maps and interfaces. It is set by
+	// the generator and not serialized, so Type stays a single name.
+	Nullable bool `json:"-"`
 }
 
 // NewJSONSchema creates a root JSON Schema object with the $schema field set.
@@ -1,5 +1,14 @@
 package schema
 
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// ErrInvalidAccessLevel is returned when decoding an unknown access level.
+var ErrInvalidAccessLevel = errors.New("invalid access level")
+
 // Options configures the behavior of JSON Schema and UI Schema generation.
 type Options struct {
 	// Translator is used to localize labels. Nil means no translation.
@@ -13,13 +22,89 @@ type Options struct {
 	// The renderer name is placed into the UI Schema element's options.
 	Renderers map[string]string
 	// RolePermissions maps role names to field permission overrides.
-	// Each permission set maps field JSON names to access levels.
+	// Each permission set maps field paths to access levels.
 	RolePermissions map[string]FieldPermissions
 	// Role is the active role to apply permissions for.
 	Role string
+	// Roles lists further active roles, combined with Role by RolePolicy.
+	Roles []string
+	// RolePolicy selects how the levels of several active roles combine.
+	// The zero value, RolePolicyPermissive, grants the least restrictive.
+	RolePolicy RolePolicy
+	// RoleInherits maps a role to the roles it inherits permissions from,
+	// in increasing priority. The role's own permissions override them.
+	RoleInherits map[string][]string
+	// OmitEmpty drops fields tagged json:",omitempty" whose value in the
+	// passed instance is empty, in both schemas and recursively for nested
+	// structs.
+	OmitEmpty bool
+	// TypeNamer names Go types wherever the generator emits a type name
+	// (e.g. the root title). Nil or an empty result falls back to
+	// parser.TypeName, which spells generics as "PageOfUser".
+	TypeNamer func(t reflect.Type) string
+	// RootTitle sets the root JSON Schema title to the type name.
+	RootTitle bool
+	// ValueDefaults uses the non-empty field values of the passed instance
+	// as JSON Schema defaults, overriding default tags.
+	ValueDefaults bool
+	// Template, when set, describes the UI Schema layout instead of the
+	// struct field order (see UISchemaTemplate).
+	Template *UISchemaTemplate
+	// Widgets configures automatic widget inference in the UI Schema.
+	// The zero value enables inference with default thresholds.
+	Widgets WidgetOptions
+	// Categorization configures the Categorization layout built from
+	// form tag categories.
+	Categorization CategorizationOptions
 }
 
-// FieldPermissions maps field JSON names to access levels.
+// CategorizationOptions configures the Categorization layout: the fallback
+// category for uncategorized fields, category order and options, and the
+// wizard (stepper) variant.
+type CategorizationOptions struct {
+	// OtherLabel renames the fallback category holding uncategorized
+	// elements. Default is "Other". The label is translated through the
+	// Translator like any other label.
+	OtherLabel string
+	// OtherI18nKey sets an explicit i18n key for the fallback category.
+	OtherI18nKey string
+	// OmitOther drops uncategorized elements instead of placing them
+	// into the fallback category.
+	OmitOther bool
+	// Order lists category names in display order. Categories not listed
+	// follow in first-seen order.
+	Order []string
+	// Categories holds per-category options (e.g. "icon", "description"),
+	// keyed by category name as written in the form tag.
+	Categories map[string]map[string]any
+	// Variant sets options.variant on the Categorization (e.g. "stepper").
+	Variant string
+	// ShowNavButtons sets options.showNavButtons, rendering next/previous
+	// buttons for the stepper variant.
+	ShowNavButtons bool
+}
+
+// WidgetOptions configures how UI widget hints (toggle, radio, slider,
+// date pickers, multi-line text) are derived from JSON Schema facts.
+// Zero-valued thresholds select the defaults.
+type WidgetOptions struct {
+	// Disabled turns widget inference off entirely.
+	Disabled bool
+	// RadioMaxEnum is the largest enum rendered as radio buttons. Default is 5.
+	RadioMaxEnum int
+	// MultilineMinLength is the maxLength above which a string is rendered
+	// as a multi-line text area. Default is 255.
+	MultilineMinLength int
+	// SliderSteps is the number of slider steps for non-integer ranges.
+	// Integer ranges always use a step of 1. Default is 100.
+	SliderSteps int
+}
+
+// FieldPermissions maps field paths to access levels. A path joins JSON
+// names with dots ("contact.email"); "[]" selects the items of an array or
+// the values of a map ("items[].price"); "*" matches any single segment and
+// "**" any number of segments ("**.salary"). An exact path takes precedence
+// over wildcards, and a field is never less restricted than its ancestors.
 type FieldPermissions map[string]AccessLevel
 
 // AccessLevel defines the access level for a field.
@@ -34,6 +119,46 @@ const (
 	AccessHidden
 )
 
+// MarshalText encodes the level as "rw", "ro" or "hidden", so that
+// FieldPermissions read naturally in JSON.
+func (l AccessLevel) MarshalText() ([]byte, error) {
+	switch l {
+	case AccessReadWrite:
+		return []byte("rw"), nil
+	case AccessReadOnly:
+		return []byte("ro"), nil
+	case AccessHidden:
+		return []byte("hidden"), nil
+	}
+
+	return nil, fmt.Errorf("%w: %d", ErrInvalidAccessLevel, int(l))
+}
+
+// UnmarshalText decodes a level name accepted by ParseAccessLevel.
+func (l *AccessLevel) UnmarshalText(text []byte) error {
+	level, ok := ParseAccessLevel(string(text))
+	if !ok {
+		return fmt.Errorf("%w: %q", ErrInvalidAccessLevel, text)
+	}
+
+	*l = level
+
+	return nil
+}
+
+// AnyRole is the access tag entry applying to roles not listed in the tag.
+const AnyRole = "*"
+
+// RolePolicy defines how the access levels of several roles combine.
+type RolePolicy int
+
+const (
+	// RolePolicyPermissive grants the least restrictive level of the roles.
+	RolePolicyPermissive RolePolicy = iota
+	// RolePolicyRestrictive grants the most restrictive level of the roles.
+	RolePolicyRestrictive
+)
+
 // DraftURL returns the $schema URL for the configured draft version.
 func (o Options) DraftURL() string {
 	if o.Draft == "2019-09" {
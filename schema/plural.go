@@ -0,0 +1,207 @@
+package schema
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// PluralParam is the message parameter that selects the plural form.
+const PluralParam = "count"
+
+// CLDR plural categories.
+const (
+	PluralZero  = "zero"
+	PluralOne   = "one"
+	PluralTwo   = "two"
+	PluralFew   = "few"
+	PluralMany  = "many"
+	PluralOther = "other"
+)
+
+// placeholderPattern matches "{name}" and go-i18n style "{{.Name}}".
+var placeholderPattern = regexp.MustCompile(`\{\{\s*\.(\w+)\s*\}\}|\{(\w+)\}`)
+
+// PluralCategory returns the CLDR plural category of the number n in the
+// language of locale ("uk-UA" uses the "uk" rules). Covered are the East
+// Slavic, Polish, Czech/Slovak, French/Portuguese and Arabic rules and the
+// languages without plurals (ja, zh, ko, …); all others use the English
+// one/other rule. Non-numeric n is "other".
+func PluralCategory(locale string, n any) string {
+	f, ok := toFloat(n)
+	if !ok {
+		return PluralOther
+	}
+
+	f = math.Abs(f)
+	i := int64(f)
+	integer := float64(i) == f
+
+	switch pluralLanguage(locale) {
+	case "ja", "zh", "ko", "vi", "th", "id", "ms", "lo", "my", "km":
+		return PluralOther
+	case "uk", "ru", "be":
+		return slavicPlural(i, integer, PluralOne)
+	case "pl":
+		if integer && i == 1 {
+			return PluralOne
+		}
+
+		return slavicPlural(i, integer, "")
+	case "cs", "sk":
+		switch {
+		case !integer:
+			return PluralMany
+		case i == 1:
+			return PluralOne
+		case i >= 2 && i <= 4:
+			return PluralFew
+		}
+
+		return PluralOther
+	case "fr", "pt":
+		if i <= 1 {
+			return PluralOne
+		}
+
+		return PluralOther
+	case "ar":
+		return arabicPlural(i, integer)
+	}
+
+	if integer && i == 1 {
+		return PluralOne
+	}
+
+	return PluralOther
+}
+
+// slavicPlural applies the East Slavic/Polish rule; one is the category
+// of 1, 21, 31, … (empty for Polish, where only 1 is "one").
+func slavicPlural(i int64, integer bool, one string) string {
+	if !integer {
+		return PluralOther
+	}
+
+	mod10, mod100 := i%10, i%100
+
+	switch {
+	case one != "" && mod10 == 1 && mod100 != 11:
+		return one
+	case mod10 >= 2 && mod10 <= 4 && (mod100 < 12 || mod100 > 14):
+		return PluralFew
+	}
+
+	return PluralMany
+}
+
+// arabicPlural applies the Arabic plural rule.
+func arabicPlural(i int64, integer bool) string {
+	if !integer {
+		return PluralOther
+	}
+
+	switch mod100 := i % 100; {
+	case i == 0:
+		return PluralZero
+	case i == 1:
+		return PluralOne
+	case i == 2:
+		return PluralTwo
+	case mod100 >= 3 && mod100 <= 10:
+		return PluralFew
+	case mod100 >= 11:
+		return PluralMany
+	}
+
+	return PluralOther
+}
+
+// pluralForms returns the plural categories of a language in the order of
+// gettext msgstr[N] indices.
+func pluralForms(locale string) []string {
+	switch pluralLanguage(locale) {
+	case "ja", "zh", "ko", "vi", "th", "id", "ms", "lo", "my", "km":
+		return []string{PluralOther}
+	case "uk", "ru", "be", "pl":
+		return []string{PluralOne, PluralFew, PluralMany}
+	case "cs", "sk":
+		return []string{PluralOne, PluralFew, PluralOther}
+	case "ar":
+		return []string{PluralZero, PluralOne, PluralTwo, PluralFew, PluralMany, PluralOther}
+	}
+
+	return []string{PluralOne, PluralOther}
+}
+
+// pluralLanguage returns the lowercase language subtag of locale.
+func pluralLanguage(locale string) string {
+	if i := strings.IndexAny(locale, "-_"); i >= 0 {
+		locale = locale[:i]
+	}
+
+	return strings.ToLower(locale)
+}
+
+// toFloat converts a numeric value (or numeric string) to float64.
+func toFloat(n any) (float64, bool) {
+	if s, ok := n.(string); ok {
+		f, err := strconv.ParseFloat(s, 64)
+		return f, err == nil
+	}
+
+	v := reflect.ValueOf(n)
+
+	switch v.Kind() { //nolint:exhaustive // only numbers have a plural form
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return float64(v.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), true
+	default:
+		return 0, false
+	}
+}
+
+// FormatMessage replaces the "{name}" and "{{.Name}}" placeholders of msg
+// with params. Names match case-insensitively; unknown placeholders are
+// kept.
+func FormatMessage(msg string, params map[string]any) string {
+	if len(params) == 0 || !strings.Contains(msg, "{") {
+		return msg
+	}
+
+	return placeholderPattern.ReplaceAllStringFunc(msg, func(match string) string {
+		sub := placeholderPattern.FindStringSubmatch(match)
+
+		name := sub[1]
+		if name == "" {
+			name = sub[2]
+		}
+
+		if v, ok := lookupParam(params, name); ok {
+			return fmt.Sprint(v)
+		}
+
+		return match
+	})
+}
+
+// lookupParam returns the parameter name, matched exactly or ignoring case.
+func lookupParam(params map[string]any, name string) (any, bool) {
+	if v, ok := params[name]; ok {
+		return v, true
+	}
+
+	for k, v := range params {
+		if strings.EqualFold(k, name) {
+			return v, true
+		}
+	}
+
+	return nil, false
+}
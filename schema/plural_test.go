@@ -0,0 +1,147 @@
+package schema_test
+
+import (
+	"testing"
+
+	"github.com/holdemlab/ui-json-schema/schema"
+)
+
+func TestPluralCategory(t *testing.T) {
+	tests := []struct {
+		locale   string
+		n        any
+		expected string
+	}{
+		{"en", 1, "one"},
+		{"en", 0, "other"},
+		{"en", 2, "other"},
+		{"en", 1.5, "other"},
+		{"en-US", int64(1), "one"},
+		{"uk", 1, "one"},
+		{"uk", 21, "one"},
+		{"uk", 11, "many"},
+		{"uk-UA", 3, "few"},
+		{"uk", 22, "few"},
+		{"uk", 12, "many"},
+		{"uk", 5, "many"},
+		{"uk", 0, "many"},
+		{"uk", 1.5, "other"},
+		{"ru", uint8(4), "few"},
+		{"pl", 1, "one"},
+		{"pl", 21, "many"},
+		{"pl", 22, "few"},
+		{"cs", 3, "few"},
+		{"cs", 5, "other"},
+		{"fr", 0, "one"},
+		{"fr", 2, "other"},
+		{"ja", 1, "other"},
+		{"ar", 0, "zero"},
+		{"ar", 2, "two"},
+		{"ar", 103, "few"},
+		{"ar", 11, "many"},
+		{"en", "1", "one"},
+		{"en", "abc", "other"},
+		{"en", nil, "other"},
+	}
+
+	for _, tt := range tests {
+		if got := schema.PluralCategory(tt.locale, tt.n); got != tt.expected {
+			t.Errorf("PluralCategory(%q, %v) = %q, want %q", tt.locale, tt.n, got, tt.expected)
+		}
+	}
+}
+
+func TestFormatMessage(t *testing.T) {
+	params := map[string]any{"min": 3, "Count": 2.0, "name": "Ім'я"}
+
+	tests := []struct {
+		msg, expected string
+	}{
+		{"At least {min} characters", "At least 3 characters"},
+		{"{{.Count}} items", "2 items"},
+		{"{count} items for {name}", "2 items for Ім'я"},
+		{"{unknown} {min}", "{unknown} 3"},
+		{"no placeholders", "no placeholders"},
+	}
+
+	for _, tt := range tests {
+		if got := schema.FormatMessage(tt.msg, params); got != tt.expected {
+			t.Errorf("FormatMessage(%q) = %q, want %q", tt.msg, got, tt.expected)
+		}
+	}
+
+	if got := schema.FormatMessage("{min}", nil); got != "{min}" {
+		t.Errorf("expected message unchanged without params, got %q", got)
+	}
+}
+
+func pluralTranslator() *schema.MapTranslator {
+	return schema.NewMapTranslator(map[string]map[string]string{
+		"uk": {
+			"files":      "{count} файлів",
+			"files#one":  "{count} файл",
+			"files#few":  "{count} файли",
+			"files#many": "{count} файлів",
+		},
+		"en": {
+			"files":     "{count} files",
+			"files#one": "{count} file",
+		},
+	})
+}
+
+func TestMapTranslator_TranslateParams(t *testing.T) {
+	tr := pluralTranslator()
+
+	tests := []struct {
+		locale   string
+		count    any
+		expected string
+	}{
+		{"uk", 1, "1 файл"},
+		{"uk", 3, "3 файли"},
+		{"uk", 11, "11 файлів"},
+		{"uk", 2.5, "2.5 файлів"},
+		{"en", 1, "1 file"},
+		{"en", 7, "7 files"},
+	}
+
+	for _, tt := range tests {
+		got := tr.TranslateParams("files", tt.locale, map[string]any{"count": tt.count})
+		if got != tt.expected {
+			t.Errorf("TranslateParams(files, %q, %v) = %q, want %q", tt.locale, tt.count, got, tt.expected)
+		}
+	}
+
+	if got := tr.TranslateParams("At least {min}", "uk", map[string]any{"min": 2}); got != "At least 2" {
+		t.Errorf("expected missing key formatted, got %q", got)
+	}
+}
+
+func TestTranslateParams_PlainTranslator(t *testing.T) {
+	var tr schema.Translator = plainTranslator{}
+
+	if got := schema.TranslateParams(tr, "{count} items", "en", map[string]any{"count": 4}); got != "4 items" {
+		t.Errorf("expected plain translator result formatted, got %q", got)
+	}
+}
+
+type plainTranslator struct{}
+
+func (plainTranslator) Translate(key, _ string) string { return key }
+
+func TestFallbackTranslator_TranslateParams(t *testing.T) {
+	tr := schema.NewFallbackTranslator(pluralTranslator(), "en")
+
+	if got := tr.TranslateParams("files", "uk-UA", map[string]any{"count": 2}); got != "2 файли" {
+		t.Errorf("expected uk plural through fallback, got %q", got)
+	}
+
+	if got := tr.TranslateParams("files", "de", map[string]any{"count": 1}); got != "1 file" {
+		t.Errorf("expected default locale plural, got %q", got)
+	}
+
+	if got := tr.TranslateParams("{count} x", "de", map[string]any{"count": 1}); got != "1 x" {
+		t.Errorf("expected missing key formatted, got %q", got)
+	}
+}
@@ -38,6 +38,16 @@ type FieldTags struct {
 	Maximum *float64
 	// Pattern holds a regex pattern constraint for string fields.
 	Pattern string
+	// KeyPattern holds a regex pattern constraint for map keys.
+	KeyPattern string
+	// KeyFormat holds a format constraint for map keys.
+	KeyFormat string
+	// ErrorMessages holds custom validation messages by JSON Schema keyword
+	// ("_" for all other errors), from the errmsg tag.
+	ErrorMessages map[string]string
+	// Access holds access levels by role name (AnyRole for unlisted roles),
+	// from the access tag.
+	Access map[string]AccessLevel
 }
 
 // ParseFieldTags extracts schema-relevant tags from a struct field.
@@ -72,12 +82,69 @@ func ParseFieldTags(field reflect.StructField) FieldTags {
 		ft.Renderer = v
 	}
 
+	if v := field.Tag.Get("keyPattern"); v != "" {
+		ft.KeyPattern = v
+	}
+
+	if v := field.Tag.Get("keyFormat"); v != "" {
+		ft.KeyFormat = v
+	}
+
+	if v := field.Tag.Get("access"); v != "" {
+		ft.Access = parseAccessTag(v)
+	}
+
 	parseRuleTags(field, &ft)
 	parseValidationTags(field, &ft)
 
 	return ft
 }
 
+// Params returns the constraints of the field as named message parameters
+// for ParamTranslator: minLength, maxLength, minimum, maximum, pattern and
+// format when set, min and max for the length or value bounds, and count
+// (min, else max) selecting the plural form. It returns nil when the field
+// has no constraints.
+func (ft FieldTags) Params() map[string]any {
+	params := make(map[string]any)
+
+	if ft.MinLength != nil {
+		params["minLength"], params["min"] = *ft.MinLength, *ft.MinLength
+	}
+
+	if ft.MaxLength != nil {
+		params["maxLength"], params["max"] = *ft.MaxLength, *ft.MaxLength
+	}
+
+	if ft.Minimum != nil {
+		params["minimum"], params["min"] = *ft.Minimum, *ft.Minimum
+	}
+
+	if ft.Maximum != nil {
+		params["maximum"], params["max"] = *ft.Maximum, *ft.Maximum
+	}
+
+	if ft.Pattern != "" {
+		params["pattern"] = ft.Pattern
+	}
+
+	if ft.Format != "" {
+		params["format"] = ft.Format
+	}
+
+	if v, ok := params["min"]; ok {
+		params[PluralParam] = v
+	} else if v, ok := params["max"]; ok {
+		params[PluralParam] = v
+	}
+
+	if len(params) == 0 {
+		return nil
+	}
+
+	return params
+}
+
 // parseRuleTags extracts conditional rule tags from a struct field.
 func parseRuleTags(field reflect.StructField, ft *FieldTags) {
 	if v := field.Tag.Get("visibleIf"); v != "" {
@@ -130,6 +197,39 @@ func parseValidationTags(field reflect.StructField, ft *FieldTags) {
 	if v := field.Tag.Get("pattern"); v != "" {
 		ft.Pattern = v
 	}
+
+	if v := field.Tag.Get("errmsg"); v != "" {
+		ft.ErrorMessages = parseErrorMessages(v)
+	}
+}
+
+// parseErrorMessages parses an errmsg tag such as
+// "pattern=Only lowercase letters;required=Name is needed". A message
+// without a keyword applies to all other errors ("_").
+func parseErrorMessages(val string) map[string]string {
+	messages := make(map[string]string)
+
+	for _, part := range strings.Split(val, ";") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		keyword, msg, ok := strings.Cut(part, "=")
+		if !ok || strings.ContainsAny(strings.TrimSpace(keyword), " \t") {
+			keyword, msg = "_", part
+		}
+
+		if keyword = strings.TrimSpace(keyword); keyword != "" {
+			messages[keyword] = strings.TrimSpace(msg)
+		}
+	}
+
+	if len(messages) == 0 {
+		return nil
+	}
+
+	return messages
 }
 
 // parseDefaultValue converts a string default value to the appropriate Go type
@@ -188,3 +288,47 @@ func parseEnumValues(val string) []any {
 
 	return result
 }
+
+// parseAccessTag parses an access tag such as "admin=rw;support=ro;*=hidden".
+// An entry with a missing or unknown level (e.g. "*=hiden") hides the
+// field from its role, so a typo fails closed.
+func parseAccessTag(val string) map[string]AccessLevel {
+	access := make(map[string]AccessLevel)
+
+	for _, part := range strings.Split(val, ";") {
+		role, level, _ := strings.Cut(part, "=")
+
+		role = strings.TrimSpace(role)
+		if role == "" {
+			continue
+		}
+
+		l, ok := ParseAccessLevel(level)
+		if !ok {
+			l = AccessHidden
+		}
+
+		access[role] = l
+	}
+
+	if len(access) == 0 {
+		return nil
+	}
+
+	return access
+}
+
+// ParseAccessLevel parses an access level name: "rw" (or "readwrite"),
+// "ro" (or "readonly") and "hidden", case-insensitively.
+func ParseAccessLevel(s string) (AccessLevel, bool) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "rw", "readwrite":
+		return AccessReadWrite, true
+	case "ro", "readonly":
+		return AccessReadOnly, true
+	case "hidden":
+		return AccessHidden, true
+	}
+
+	return AccessReadWrite, false
+}
@@ -1,6 +1,8 @@
 package schema_test
 
 import (
+	"encoding/json"
+	"errors"
 	"reflect"
 	"testing"
 
@@ -443,3 +445,151 @@ func TestParseFieldTags_ValidationCombined(t *testing.T) {
 		t.Errorf("expected Description 'Your full name', got %q", tags.Description)
 	}
 }
+
+type tagKeyConstraints struct {
+	Rates map[string]float64 `keyPattern:"^[A-Z]{3}$" keyFormat:"currency"`
+}
+
+func TestParseFieldTags_KeyConstraints(t *testing.T) {
+	field, _ := reflect.TypeOf(tagKeyConstraints{}).FieldByName("Rates")
+	ft := schema.ParseFieldTags(field)
+
+	if ft.KeyPattern != "^[A-Z]{3}$" {
+		t.Errorf("expected key pattern, got %q", ft.KeyPattern)
+	}
+
+	if ft.KeyFormat != "currency" {
+		t.Errorf("expected key format, got %q", ft.KeyFormat)
+	}
+}
+
+type tagParams struct {
+	Name  string  `json:"name" minLength:"3" maxLength:"20" pattern:"^[a-z]+$"`
+	Price float64 `json:"price" maximum:"99.5"`
+	Plain string  `json:"plain"`
+}
+
+func TestFieldTags_Params(t *testing.T) {
+	field, _ := reflect.TypeOf(tagParams{}).FieldByName("Name")
+	params := schema.ParseFieldTags(field).Params()
+
+	expected := map[string]any{
+		"minLength": 3, "maxLength": 20, "min": 3, "max": 20,
+		"pattern": "^[a-z]+$", "count": 3,
+	}
+	if !reflect.DeepEqual(params, expected) {
+		t.Errorf("unexpected params %v", params)
+	}
+
+	field, _ = reflect.TypeOf(tagParams{}).FieldByName("Price")
+	if params := schema.ParseFieldTags(field).Params(); params["max"] != 99.5 || params["count"] != 99.5 {
+		t.Errorf("expected max as count, got %v", params)
+	}
+
+	field, _ = reflect.TypeOf(tagParams{}).FieldByName("Plain")
+	if params := schema.ParseFieldTags(field).Params(); params != nil {
+		t.Errorf("expected nil params, got %v", params)
+	}
+}
+
+type tagErrmsg struct {
+	Name  string `json:"name" errmsg:"pattern=Only lowercase letters; required=Name is needed"`
+	Email string `json:"email" errmsg:"Enter a valid email"`
+	Code  string `json:"code" errmsg:"Must be a = b;minLength=Too short"`
+}
+
+func TestParseFieldTags_ErrorMessages(t *testing.T) {
+	tests := []struct {
+		field    string
+		expected map[string]string
+	}{
+		{"Name", map[string]string{"pattern": "Only lowercase letters", "required": "Name is needed"}},
+		{"Email", map[string]string{"_": "Enter a valid email"}},
+		{"Code", map[string]string{"_": "Must be a = b", "minLength": "Too short"}},
+	}
+
+	for _, tt := range tests {
+		field, _ := reflect.TypeOf(tagErrmsg{}).FieldByName(tt.field)
+		if got := schema.ParseFieldTags(field).ErrorMessages; !reflect.DeepEqual(got, tt.expected) {
+			t.Errorf("%s: got %v, want %v", tt.field, got, tt.expected)
+		}
+	}
+}
+
+type tagAccess struct {
+	Salary int    `json:"salary" access:"admin=rw; support=RO;*=hidden"`
+	Notes  string `json:"notes" access:"admin=owner;support"`
+	Bonus  int    `json:"bonus" access:"admin=rw;*=hiden"`
+	Name   string `json:"name"`
+}
+
+func TestParseFieldTags_Access(t *testing.T) {
+	tests := []struct {
+		field    string
+		expected map[string]schema.AccessLevel
+	}{
+		{"Salary", map[string]schema.AccessLevel{
+			"admin":        schema.AccessReadWrite,
+			"support":      schema.AccessReadOnly,
+			schema.AnyRole: schema.AccessHidden,
+		}},
+		// Unknown or missing levels fail closed.
+		{"Notes", map[string]schema.AccessLevel{"admin": schema.AccessHidden, "support": schema.AccessHidden}},
+		{"Bonus", map[string]schema.AccessLevel{"admin": schema.AccessReadWrite, schema.AnyRole: schema.AccessHidden}},
+		{"Name", nil},
+	}
+
+	for _, tt := range tests {
+		field, _ := reflect.TypeOf(tagAccess{}).FieldByName(tt.field)
+		if got := schema.ParseFieldTags(field).Access; !reflect.DeepEqual(got, tt.expected) {
+			t.Errorf("%s: got %v, want %v", tt.field, got, tt.expected)
+		}
+	}
+}
+
+func TestParseAccessLevel(t *testing.T) {
+	tests := []struct {
+		in       string
+		expected schema.AccessLevel
+		ok       bool
+	}{
+		{"rw", schema.AccessReadWrite, true},
+		{"ReadWrite", schema.AccessReadWrite, true},
+		{" ro ", schema.AccessReadOnly, true},
+		{"readonly", schema.AccessReadOnly, true},
+		{"hidden", schema.AccessHidden, true},
+		{"none", schema.AccessReadWrite, false},
+	}
+
+	for _, tt := range tests {
+		if got, ok := schema.ParseAccessLevel(tt.in); got != tt.expected || ok != tt.ok {
+			t.Errorf("ParseAccessLevel(%q) = %v, %v; want %v, %v", tt.in, got, ok, tt.expected, tt.ok)
+		}
+	}
+}
+
+func TestAccessLevel_JSON(t *testing.T) {
+	perms := schema.FieldPermissions{"name": schema.AccessReadOnly, "salary": schema.AccessHidden, "note": schema.AccessReadWrite}
+
+	data, err := json.Marshal(perms)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if string(data) != `{"name":"ro","note":"rw","salary":"hidden"}` {
+		t.Errorf("unexpected JSON %s", data)
+	}
+
+	var decoded schema.FieldPermissions
+	if err := json.Unmarshal(data, &decoded); err != nil || !reflect.DeepEqual(decoded, perms) {
+		t.Errorf("round trip = %v, %v; want %v", decoded, err, perms)
+	}
+
+	if err := json.Unmarshal([]byte(`{"name":"none"}`), &decoded); !errors.Is(err, schema.ErrInvalidAccessLevel) {
+		t.Errorf("expected ErrInvalidAccessLevel, got %v", err)
+	}
+
+	if _, err := json.Marshal(schema.FieldPermissions{"name": schema.AccessLevel(9)}); !errors.Is(err, schema.ErrInvalidAccessLevel) {
+		t.Errorf("expected ErrInvalidAccessLevel, got %v", err)
+	}
+}
@@ -0,0 +1,40 @@
+package schema
+
+import "encoding/json"
+
+// UISchemaTemplate describes a form layout separately from the Go struct,
+// so designers can rearrange a form without reordering struct fields.
+//
+// Layout nodes (VerticalLayout, HorizontalLayout, Group, Categorization,
+// Category) are emitted as described. A node with Field set is replaced by
+// the element generated for that field — a Control, or a Group for nested
+// structs — with the node's Label, Options and Rule applied on top.
+// Fields not referenced by the template are appended after the template
+// layout so new struct fields never disappear silently.
+type UISchemaTemplate struct {
+	// Type is the layout type. Empty means VerticalLayout (or Control when
+	// Field is set).
+	Type string `json:"type,omitempty"`
+	// Label is the layout label, or a label override for a field.
+	Label string `json:"label,omitempty"`
+	// I18n is a translation key for Label.
+	I18n string `json:"i18n,omitempty"`
+	// Field is the dotted JSON path of the referenced field ("address.city").
+	Field string `json:"field,omitempty"`
+	// Elements holds the child nodes of a layout.
+	Elements []*UISchemaTemplate `json:"elements,omitempty"`
+	// Options are merged into the generated element's options.
+	Options map[string]any `json:"options,omitempty"`
+	// Rule replaces the generated element's rule.
+	Rule *UISchemaRule `json:"rule,omitempty"`
+}
+
+// ParseUISchemaTemplate decodes a UISchemaTemplate from its JSON form.
+func ParseUISchemaTemplate(data []byte) (*UISchemaTemplate, error) {
+	var tmpl UISchemaTemplate
+	if err := json.Unmarshal(data, &tmpl); err != nil {
+		return nil, err
+	}
+
+	return &tmpl, nil
+}
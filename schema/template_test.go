@@ -0,0 +1,47 @@
+package schema_test
+
+import (
+	"testing"
+
+	"github.com/holdemlab/ui-json-schema/schema"
+)
+
+func TestParseUISchemaTemplate(t *testing.T) {
+	data := []byte(`{
+		"type": "Categorization",
+		"elements": [
+			{"type": "Category", "label": "Main", "elements": [
+				{"field": "name", "label": "Full name"},
+				{"field": "address.city", "options": {"trim": true}}
+			]}
+		]
+	}`)
+
+	tmpl, err := schema.ParseUISchemaTemplate(data)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if tmpl.Type != "Categorization" || len(tmpl.Elements) != 1 {
+		t.Fatalf("unexpected template root: %+v", tmpl)
+	}
+
+	cat := tmpl.Elements[0]
+	if cat.Label != "Main" || len(cat.Elements) != 2 {
+		t.Fatalf("unexpected category: %+v", cat)
+	}
+
+	if cat.Elements[0].Field != "name" || cat.Elements[0].Label != "Full name" {
+		t.Errorf("unexpected field node: %+v", cat.Elements[0])
+	}
+
+	if cat.Elements[1].Options["trim"] != true {
+		t.Errorf("expected options on field node, got %v", cat.Elements[1].Options)
+	}
+}
+
+func TestParseUISchemaTemplate_Invalid(t *testing.T) {
+	if _, err := schema.ParseUISchemaTemplate([]byte(`{invalid`)); err == nil {
+		t.Fatal("expected error for invalid JSON")
+	}
+}
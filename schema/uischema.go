@@ -1,6 +1,7 @@
 package schema
 
 import (
+	"encoding/json"
 	"strconv"
 	"strings"
 )
@@ -115,6 +116,49 @@ type FormOptions struct {
 	DisableIf string
 	// I18nKey holds an i18n translation key for the Category label.
 	I18nKey string
+	// Widget overrides the inferred widget for the field.
+	// Supported: toggle, checkbox, radio, select, slider, input, multi,
+	// text, date, time, date-time and none (no inferred hints).
+	Widget string
+	// Order positions the element among its siblings. Elements are stably
+	// sorted by Order; fields without it count as 0.
+	Order *int
+	// Group wraps all sibling fields with the same name into a single Group
+	// layout labeled with that name. Groups may be placed inside a category.
+	Group string
+	// Options holds JSON Forms control options passed through verbatim,
+	// set either with a known key (placeholder, trim, ...) or with the
+	// generic "opt.<name>=<value>" syntax.
+	Options map[string]any
+}
+
+// passThroughOptions lists JSON Forms control options accepted as plain
+// form tag keys. A key without a value is set to true.
+var passThroughOptions = map[string]bool{
+	"placeholder":              true,
+	"showUnfocusedDescription": true,
+	"hideRequiredAsterisk":     true,
+	"trim":                     true,
+	"restrict":                 true,
+	"suggestion":               true,
+	"showSortButtons":          true,
+	"elementLabelProp":         true,
+	"toggle":                   true,
+	"slider":                   true,
+	"format":                   true,
+	"focus":                    true,
+	"autocomplete":             true,
+	"step":                     true,
+	"accept":                   true,
+}
+
+// stringOptions lists pass-through options whose value is always a string,
+// even when it looks like a number or a boolean.
+var stringOptions = map[string]bool{
+	"placeholder":      true,
+	"elementLabelProp": true,
+	"format":           true,
+	"accept":           true,
 }
 
 // ParseFormTag parses a form struct tag value like "label=Full name;multiline;readonly".
@@ -158,8 +202,22 @@ func ParseFormTag(tag string) FormOptions {
 			if hasValue {
 				opts.I18nKey = strings.TrimSpace(value)
 			}
+		case "widget":
+			if hasValue {
+				opts.Widget = strings.TrimSpace(value)
+			}
+		case "order":
+			if n, err := strconv.Atoi(strings.TrimSpace(value)); hasValue && err == nil {
+				opts.Order = &n
+			}
+		case "group":
+			if hasValue {
+				opts.Group = strings.TrimSpace(value)
+			}
 		default:
-			parseFormRulePart(key, value, hasValue, &opts)
+			if !parseFormOptionPart(key, value, hasValue, &opts) {
+				parseFormRulePart(key, value, hasValue, &opts)
+			}
 		}
 	}
 
@@ -177,6 +235,63 @@ func parseFormLayoutPart(layoutVal string, opts *FormOptions) {
 	}
 }
 
+// parseFormOptionPart handles pass-through control options: known JSON Forms
+// option keys and the generic "opt.<name>=<value>" syntax. Returns false
+// when the key is not a control option.
+func parseFormOptionPart(key, value string, hasValue bool, opts *FormOptions) bool {
+	name, generic := strings.CutPrefix(key, "opt.")
+	if !generic && !passThroughOptions[key] {
+		return false
+	}
+
+	if name == "" {
+		return true
+	}
+
+	if opts.Options == nil {
+		opts.Options = make(map[string]any)
+	}
+
+	switch {
+	case !hasValue:
+		opts.Options[name] = true
+	case stringOptions[name]:
+		opts.Options[name] = strings.TrimSpace(value)
+	case name == "suggestion":
+		opts.Options[name] = parseSuggestionValue(strings.TrimSpace(value))
+	default:
+		opts.Options[name] = ParseOptionValue(value)
+	}
+
+	return true
+}
+
+// ParseOptionValue converts a form tag option value to a typed value.
+// JSON arrays, objects and quoted strings are decoded as JSON; "true"/"false"
+// become bool; numbers become int64 or float64; anything else is a string.
+func ParseOptionValue(val string) any {
+	val = strings.TrimSpace(val)
+
+	if val != "" && strings.ContainsRune("[{\"", rune(val[0])) {
+		var v any
+		if err := json.Unmarshal([]byte(val), &v); err == nil {
+			return v
+		}
+	}
+
+	return parseConditionValue(val)
+}
+
+// parseSuggestionValue parses a suggestion list given either as a JSON array
+// or as comma-separated values.
+func parseSuggestionValue(val string) any {
+	if strings.HasPrefix(val, "[") {
+		return ParseOptionValue(val)
+	}
+
+	return parseEnumValues(val)
+}
+
 // parseFormRulePart handles rule-related keys inside a form tag
 // (visibleIf, hideIf, enableIf, disableIf).
 func parseFormRulePart(key, value string, hasValue bool, opts *FormOptions) {
@@ -550,3 +550,120 @@ func TestUISchemaElement_I18nOmitEmpty(t *testing.T) {
 		t.Error("expected i18n to be omitted when empty")
 	}
 }
+
+func TestParseFormTag_Widget(t *testing.T) {
+	opts := schema.ParseFormTag("label=Active;widget=checkbox")
+	if opts.Widget != "checkbox" {
+		t.Errorf("expected widget 'checkbox', got %q", opts.Widget)
+	}
+
+	if opts.Label != "Active" {
+		t.Errorf("expected label 'Active', got %q", opts.Label)
+	}
+}
+
+func TestParseFormTag_PassThroughKnownKeys(t *testing.T) {
+	opts := schema.ParseFormTag("placeholder=123;trim;focus=false;elementLabelProp=name;suggestion=red, green")
+
+	if opts.Options["placeholder"] != "123" {
+		t.Errorf("expected string placeholder '123', got %#v", opts.Options["placeholder"])
+	}
+
+	if opts.Options["trim"] != true {
+		t.Errorf("expected trim=true, got %#v", opts.Options["trim"])
+	}
+
+	if opts.Options["focus"] != false {
+		t.Errorf("expected focus=false, got %#v", opts.Options["focus"])
+	}
+
+	if opts.Options["elementLabelProp"] != "name" {
+		t.Errorf("expected elementLabelProp 'name', got %#v", opts.Options["elementLabelProp"])
+	}
+
+	suggestion, ok := opts.Options["suggestion"].([]any)
+	if !ok || len(suggestion) != 2 || suggestion[0] != "red" || suggestion[1] != "green" {
+		t.Errorf("expected suggestion [red green], got %#v", opts.Options["suggestion"])
+	}
+}
+
+func TestParseFormTag_PassThroughGeneric(t *testing.T) {
+	opts := schema.ParseFormTag(`opt.maxRows=5;opt.ratio=0.5;opt.dense;opt.variant=outlined;opt.colors=["red","blue"];opt.detail={"type":"VerticalLayout"}`)
+
+	tests := map[string]any{
+		"maxRows": int64(5),
+		"ratio":   0.5,
+		"dense":   true,
+		"variant": "outlined",
+	}
+
+	for key, want := range tests {
+		if got := opts.Options[key]; got != want {
+			t.Errorf("opt.%s: expected %#v, got %#v", key, want, got)
+		}
+	}
+
+	if colors, ok := opts.Options["colors"].([]any); !ok || len(colors) != 2 {
+		t.Errorf("expected JSON array for colors, got %#v", opts.Options["colors"])
+	}
+
+	detail, ok := opts.Options["detail"].(map[string]any)
+	if !ok || detail["type"] != "VerticalLayout" {
+		t.Errorf("expected JSON object for detail, got %#v", opts.Options["detail"])
+	}
+}
+
+func TestParseFormTag_PassThroughDoesNotShadowKnownDirectives(t *testing.T) {
+	opts := schema.ParseFormTag("label=Name;readonly;visibleIf=active:true")
+
+	if opts.Options != nil {
+		t.Errorf("expected no pass-through options, got %v", opts.Options)
+	}
+
+	if opts.VisibleIf != "active:true" {
+		t.Errorf("expected visibleIf to be parsed, got %q", opts.VisibleIf)
+	}
+}
+
+func TestParseOptionValue(t *testing.T) {
+	tests := []struct {
+		in   string
+		want any
+	}{
+		{"true", true},
+		{"42", int64(42)},
+		{"1.5", 1.5},
+		{`"007"`, "007"},
+		{"plain text", "plain text"},
+		{"[broken", "[broken"},
+	}
+
+	for _, tt := range tests {
+		if got := schema.ParseOptionValue(tt.in); got != tt.want {
+			t.Errorf("ParseOptionValue(%q) = %#v, want %#v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestParseFormTag_OrderAndGroup(t *testing.T) {
+	opts := schema.ParseFormTag("order=-2;group=Billing;category=Account")
+
+	if opts.Order == nil || *opts.Order != -2 {
+		t.Errorf("expected order -2, got %v", opts.Order)
+	}
+
+	if opts.Group != "Billing" {
+		t.Errorf("expected group 'Billing', got %q", opts.Group)
+	}
+
+	if opts.Category != "Account" {
+		t.Errorf("expected category 'Account', got %q", opts.Category)
+	}
+}
+
+func TestParseFormTag_OrderInvalid(t *testing.T) {
+	opts := schema.ParseFormTag("order=first")
+	if opts.Order != nil {
+		t.Errorf("expected nil order for invalid value, got %d", *opts.Order)
+	}
+}
@@ -0,0 +1,356 @@
+package schema
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// ValidationError describes a value that violates a JSON Schema keyword.
+// Its fields follow the ajv error object, so server-side and client-side
+// errors can be handled alike.
+type ValidationError struct {
+	// InstancePath is the JSON Pointer of the invalid value ("" for the
+	// root). Missing required properties are reported on their object.
+	InstancePath string `json:"instancePath"`
+	// Keyword is the violated keyword ("type", "required", "minLength"...).
+	Keyword string `json:"keyword"`
+	// Message describes the error, or holds the errorMessage of the schema.
+	Message string `json:"message"`
+	// Params holds keyword details, e.g. "limit" or "missingProperty".
+	Params map[string]any `json:"params,omitempty"`
+}
+
+// Error implements the error interface.
+func (e ValidationError) Error() string {
+	if e.InstancePath == "" {
+		return e.Message
+	}
+
+	return e.InstancePath + ": " + e.Message
+}
+
+// Validate checks data against s and returns the violations, or nil when
+// data is valid. data may be a Go value, encoded JSON ([]byte or
+// json.RawMessage) or the result of json.Unmarshal into any. It checks
+// type, required, enum, const, minimum, maximum, minLength, maxLength,
+// pattern, format, contentEncoding, minItems, maxItems, items, properties,
+// additionalProperties, propertyNames and oneOf; custom errorMessage
+// entries replace the default messages. Unknown formats are accepted; a
+// pattern Go cannot compile is reported as a "pattern" error. null is
+// valid wherever s is Nullable.
+func Validate(s *JSONSchema, data any) []ValidationError {
+	value, err := normalizeJSON(data)
+	if err != nil {
+		return []ValidationError{{Keyword: "type", Message: "must be valid JSON: " + err.Error()}}
+	}
+
+	var v validator
+	v.validate(s, value, "")
+
+	return v.errs
+}
+
+// normalizeJSON converts data to the generic form produced by
+// json.Unmarshal: map[string]any, []any, string, float64, bool or nil.
+func normalizeJSON(data any) (any, error) {
+	raw, ok := data.([]byte)
+	if rm, isRaw := data.(json.RawMessage); isRaw {
+		raw, ok = rm, true
+	}
+
+	if !ok {
+		var err error
+		if raw, err = json.Marshal(data); err != nil {
+			return nil, err
+		}
+	}
+
+	var value any
+	if err := json.Unmarshal(raw, &value); err != nil {
+		return nil, err
+	}
+
+	return value, nil
+}
+
+// validator collects the errors of one Validate call.
+type validator struct {
+	errs []ValidationError
+}
+
+// fail records an error, preferring the custom message of s for keyword.
+func (v *validator) fail(s *JSONSchema, path, keyword, msg string, params map[string]any) {
+	if custom := customMessage(s, keyword); custom != "" {
+		msg = custom
+	}
+
+	v.errs = append(v.errs, ValidationError{InstancePath: path, Keyword: keyword, Message: msg, Params: params})
+}
+
+// customMessage returns the errorMessage of s for keyword, else its
+// catch-all "_" message, else "".
+func customMessage(s *JSONSchema, keyword string) string {
+	if msg, ok := s.ErrorMessage[keyword].(string); ok {
+		return msg
+	}
+
+	msg, _ := s.ErrorMessage["_"].(string)
+
+	return msg
+}
+
+// validate checks value against s; path is the JSON Pointer of value.
+func (v *validator) validate(s *JSONSchema, value any, path string) {
+	if s == nil || value == nil && s.Nullable {
+		return
+	}
+
+	if !v.validateType(s, value, path) {
+		return
+	}
+
+	if len(s.Enum) > 0 && !containsJSON(s.Enum, value) {
+		v.fail(s, path, "enum", "must be equal to one of the allowed values", map[string]any{"allowedValues": s.Enum})
+	}
+
+	if s.Const != nil && !equalJSON(s.Const, value) {
+		v.fail(s, path, "const", "must be equal to constant", map[string]any{"allowedValue": s.Const})
+	}
+
+	switch val := value.(type) {
+	case float64:
+		v.validateNumber(s, val, path)
+	case string:
+		v.validateString(s, val, path)
+	case []any:
+		v.validateArray(s, val, path)
+	case map[string]any:
+		v.validateObject(s, val, path)
+	}
+
+	if len(s.OneOf) > 0 {
+		v.validateOneOf(s, value, path)
+	}
+}
+
+// validateType checks the type keyword and reports whether value has the
+// expected type, so the remaining keywords apply.
+func (v *validator) validateType(s *JSONSchema, value any, path string) bool {
+	if s.Type == "" || hasJSONType(value, s.Type) {
+		return true
+	}
+
+	v.fail(s, path, "type", "must be "+s.Type, map[string]any{"type": s.Type})
+
+	return false
+}
+
+// hasJSONType reports whether a normalized value is of the JSON type t.
+func hasJSONType(value any, t string) bool {
+	switch val := value.(type) {
+	case nil:
+		return t == "null"
+	case bool:
+		return t == "boolean"
+	case float64:
+		return t == "number" || (t == "integer" && val == math.Trunc(val))
+	case string:
+		return t == "string"
+	case []any:
+		return t == "array"
+	case map[string]any:
+		return t == "object"
+	}
+
+	return false
+}
+
+// validateNumber checks minimum and maximum.
+func (v *validator) validateNumber(s *JSONSchema, n float64, path string) {
+	if s.Minimum != nil && n < *s.Minimum {
+		v.fail(s, path, "minimum", "must be >= "+formatNumber(*s.Minimum), map[string]any{"comparison": ">=", "limit": *s.Minimum})
+	}
+
+	if s.Maximum != nil && n > *s.Maximum {
+		v.fail(s, path, "maximum", "must be <= "+formatNumber(*s.Maximum), map[string]any{"comparison": "<=", "limit": *s.Maximum})
+	}
+}
+
+// validateString checks minLength, maxLength, pattern, format and
+// contentEncoding. Lengths count characters, not bytes.
+func (v *validator) validateString(s *JSONSchema, str, path string) {
+	length := utf8.RuneCountInString(str)
+
+	if s.MinLength != nil && length < *s.MinLength {
+		v.fail(s, path, "minLength", fmt.Sprintf("must NOT have fewer than %d characters", *s.MinLength), map[string]any{"limit": *s.MinLength})
+	}
+
+	if s.MaxLength != nil && length > *s.MaxLength {
+		v.fail(s, path, "maxLength", fmt.Sprintf("must NOT have more than %d characters", *s.MaxLength), map[string]any{"limit": *s.MaxLength})
+	}
+
+	if s.Pattern != "" {
+		// A pattern that cannot be compiled fails instead of passing
+		// every value unchecked.
+		if re, err := compilePattern(s.Pattern); err != nil {
+			v.fail(s, path, "pattern", fmt.Sprintf("pattern %q cannot be checked: %v", s.Pattern, err), map[string]any{"pattern": s.Pattern})
+		} else if !re.MatchString(str) {
+			v.fail(s, path, "pattern", fmt.Sprintf("must match pattern %q", s.Pattern), map[string]any{"pattern": s.Pattern})
+		}
+	}
+
+	if s.Format != "" && !ValidFormat(s.Format, str) {
+		v.fail(s, path, "format", fmt.Sprintf("must match format %q", s.Format), map[string]any{"format": s.Format})
+	}
+
+	if s.ContentEncoding == "base64" {
+		if _, err := base64.StdEncoding.DecodeString(str); err != nil {
+			v.fail(s, path, "contentEncoding", `must be encoded as "base64"`, map[string]any{"contentEncoding": s.ContentEncoding})
+		}
+	}
+}
+
+// validateArray checks minItems, maxItems and items.
+func (v *validator) validateArray(s *JSONSchema, items []any, path string) {
+	if s.MinItems != nil && len(items) < *s.MinItems {
+		v.fail(s, path, "minItems", fmt.Sprintf("must NOT have fewer than %d items", *s.MinItems), map[string]any{"limit": *s.MinItems})
+	}
+
+	if s.MaxItems != nil && len(items) > *s.MaxItems {
+		v.fail(s, path, "maxItems", fmt.Sprintf("must NOT have more than %d items", *s.MaxItems), map[string]any{"limit": *s.MaxItems})
+	}
+
+	for i, item := range items {
+		v.validate(s.Items, item, path+"/"+strconv.Itoa(i))
+	}
+}
+
+// validateObject checks required, properties, additionalProperties and
+// propertyNames, visiting keys in sorted order for stable results.
+func (v *validator) validateObject(s *JSONSchema, obj map[string]any, path string) {
+	for _, name := range s.Required {
+		if _, ok := obj[name]; !ok {
+			v.failRequired(s, path, name)
+		}
+	}
+
+	keys := make([]string, 0, len(obj))
+	for k := range obj {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		if s.PropertyNames != nil {
+			v.validatePropertyName(s.PropertyNames, k, path)
+		}
+
+		if prop, ok := s.Properties[k]; ok {
+			v.validate(prop, obj[k], path+"/"+EscapePointer(k))
+		} else {
+			v.validate(s.AdditionalProperties, obj[k], path+"/"+EscapePointer(k))
+		}
+	}
+}
+
+// failRequired records a missing property, preferring the message set for
+// it in errorMessage.required.
+func (v *validator) failRequired(s *JSONSchema, path, name string) {
+	params := map[string]any{"missingProperty": name}
+
+	if messages, ok := s.ErrorMessage["required"].(map[string]any); ok {
+		if msg, ok := messages[name].(string); ok {
+			v.errs = append(v.errs, ValidationError{InstancePath: path, Keyword: "required", Message: msg, Params: params})
+			return
+		}
+	}
+
+	v.fail(s, path, "required", fmt.Sprintf("must have required property '%s'", name), params)
+}
+
+// validatePropertyName checks an object key against the propertyNames
+// schema and reports a violation on the object.
+func (v *validator) validatePropertyName(names *JSONSchema, key, path string) {
+	var sub validator
+	sub.validate(names, key, path)
+
+	if len(sub.errs) > 0 {
+		v.fail(names, path, "propertyNames", fmt.Sprintf("property name '%s' is invalid: %s", key, sub.errs[0].Message), map[string]any{"propertyName": key})
+	}
+}
+
+// validateOneOf checks that exactly one branch of oneOf matches.
+func (v *validator) validateOneOf(s *JSONSchema, value any, path string) {
+	passing := 0
+
+	for _, branch := range s.OneOf {
+		var sub validator
+		if sub.validate(branch, value, path); len(sub.errs) == 0 {
+			passing++
+		}
+	}
+
+	if passing != 1 {
+		v.fail(s, path, "oneOf", "must match exactly one schema in oneOf", map[string]any{"passingSchemas": passing})
+	}
+}
+
+// containsJSON reports whether values holds a value equal to value.
+func containsJSON(values []any, value any) bool {
+	for _, allowed := range values {
+		if equalJSON(allowed, value) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// equalJSON compares a schema value with a normalized instance value by
+// their JSON representation, so 1 equals 1.0 and struct values equal maps.
+func equalJSON(schemaValue, value any) bool {
+	normalized, err := normalizeJSON(schemaValue)
+
+	return err == nil && reflect.DeepEqual(normalized, value)
+}
+
+// EscapePointer escapes a JSON Pointer reference token (RFC 6901).
+func EscapePointer(token string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~", "~0"), "/", "~1")
+}
+
+// formatNumber formats a limit without a trailing ".0" for integers.
+func formatNumber(n float64) string {
+	return strconv.FormatFloat(n, 'f', -1, 64)
+}
+
+// patternCache holds compiled pattern regexps by source.
+var patternCache sync.Map
+
+// compilePattern compiles and caches a pattern. Patterns Go cannot compile
+// (e.g. ECMAScript lookarounds) return the compile error.
+func compilePattern(pattern string) (*regexp.Regexp, error) {
+	if re, ok := patternCache.Load(pattern); ok {
+		compiled, _ := re.(*regexp.Regexp)
+		return compiled, nil
+	}
+
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	patternCache.Store(pattern, re)
+
+	return re, nil
+}
@@ -0,0 +1,279 @@
+package schema_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/holdemlab/ui-json-schema/schema"
+)
+
+func intPtr(n int) *int { return &n }
+
+func floatPtr(n float64) *float64 { return &n }
+
+// orderSchema describes an order with nested items and a map of notes.
+func orderSchema() *schema.JSONSchema {
+	return &schema.JSONSchema{
+		Type:     "object",
+		Required: []string{"id", "email", "items"},
+		Properties: map[string]*schema.JSONSchema{
+			"id":     {Type: "integer", Minimum: floatPtr(1)},
+			"email":  {Type: "string", Format: "email"},
+			"status": {Type: "string", Enum: []any{"new", "paid"}},
+			"kind":   {Type: "string", Const: "order"},
+			"code":   {Type: "string", Pattern: "^[A-Z]{3}$", MinLength: intPtr(3), MaxLength: intPtr(3)},
+			"items": {
+				Type:     "array",
+				MinItems: intPtr(1),
+				MaxItems: intPtr(2),
+				Items: &schema.JSONSchema{
+					Type:     "object",
+					Required: []string{"sku"},
+					Properties: map[string]*schema.JSONSchema{
+						"sku":   {Type: "string"},
+						"price": {Type: "number", Maximum: floatPtr(100)},
+					},
+				},
+			},
+			"notes": {
+				Type:                 "object",
+				AdditionalProperties: &schema.JSONSchema{Type: "string", MaxLength: intPtr(5)},
+				PropertyNames:        &schema.JSONSchema{Pattern: "^[a-z/~]+$"},
+			},
+		},
+	}
+}
+
+func TestValidate_Valid(t *testing.T) {
+	data := map[string]any{
+		"id":     7,
+		"email":  "a@example.com",
+		"status": "paid",
+		"kind":   "order",
+		"code":   "ABC",
+		"items":  []any{map[string]any{"sku": "x", "price": 99.5}},
+		"notes":  map[string]any{"a": "hi"},
+	}
+
+	if errs := schema.Validate(orderSchema(), data); errs != nil {
+		t.Errorf("expected no errors, got %v", errs)
+	}
+}
+
+func TestValidate_Errors(t *testing.T) {
+	data := `{
+		"id": 1.5,
+		"email": "not an email",
+		"status": "lost",
+		"kind": "invoice",
+		"code": "abcd",
+		"items": [{"price": 120}, {"sku": 3}, {"sku": "z"}],
+		"notes": {"a/b~": "too long", "B": "ok"}
+	}`
+
+	got := schema.Validate(orderSchema(), json.RawMessage(data))
+
+	expected := []struct{ path, keyword string }{
+		{"/code", "maxLength"},
+		{"/code", "pattern"},
+		{"/email", "format"},
+		{"/id", "type"},
+		{"/items", "maxItems"},
+		{"/items/0", "required"},
+		{"/items/0/price", "maximum"},
+		{"/items/1/sku", "type"},
+		{"/kind", "const"},
+		{"/notes", "propertyNames"},
+		{"/notes/a~1b~0", "maxLength"},
+		{"/status", "enum"},
+	}
+
+	if len(got) != len(expected) {
+		t.Fatalf("expected %d errors, got %d: %v", len(expected), len(got), got)
+	}
+
+	for i, e := range expected {
+		if got[i].InstancePath != e.path || got[i].Keyword != e.keyword {
+			t.Errorf("error %d: got %s %s (%s), want %s %s", i, got[i].InstancePath, got[i].Keyword, got[i].Message, e.path, e.keyword)
+		}
+	}
+
+	if got[5].Params["missingProperty"] != "sku" || got[5].Message != "must have required property 'sku'" {
+		t.Errorf("unexpected required error %+v", got[5])
+	}
+}
+
+func TestValidate_MissingRequiredAtRoot(t *testing.T) {
+	errs := schema.Validate(orderSchema(), map[string]any{"items": []any{}})
+
+	var missing []string
+
+	for _, e := range errs {
+		if e.Keyword == "required" && e.InstancePath == "" {
+			missing = append(missing, e.Params["missingProperty"].(string)) //nolint:forcetypeassert // test
+		}
+	}
+
+	if !reflect.DeepEqual(missing, []string{"id", "email"}) {
+		t.Errorf("expected id and email missing, got %v (%v)", missing, errs)
+	}
+
+	if errs[len(errs)-1].Keyword != "minItems" {
+		t.Errorf("expected minItems error for the empty items, got %v", errs)
+	}
+}
+
+func TestValidate_GoValues(t *testing.T) {
+	type item struct {
+		SKU string `json:"sku"`
+	}
+
+	type order struct {
+		ID    int    `json:"id"`
+		Email string `json:"email"`
+		Items []item `json:"items"`
+	}
+
+	if errs := schema.Validate(orderSchema(), order{ID: 2, Email: "b@example.com", Items: []item{{SKU: "a"}}}); errs != nil {
+		t.Errorf("expected struct value to be valid, got %v", errs)
+	}
+
+	if errs := schema.Validate(orderSchema(), []byte(`{`)); len(errs) != 1 {
+		t.Errorf("expected a single error for invalid JSON, got %v", errs)
+	}
+}
+
+func TestValidate_ErrorMessages(t *testing.T) {
+	s := &schema.JSONSchema{
+		Type:     "object",
+		Required: []string{"login", "email"},
+		ErrorMessage: map[string]any{
+			"required": map[string]any{"login": "Login is needed"},
+		},
+		Properties: map[string]*schema.JSONSchema{
+			"login": {Type: "string", MinLength: intPtr(3), ErrorMessage: map[string]any{"minLength": "At least 3 characters"}},
+			"email": {Type: "string", Format: "email", ErrorMessage: map[string]any{"_": "Enter a valid email"}},
+			"age":   {Type: "integer"},
+		},
+	}
+
+	errs := schema.Validate(s, map[string]any{"age": "x"})
+	messages := make([]string, 0, len(errs))
+
+	for _, e := range errs {
+		messages = append(messages, e.Message)
+	}
+
+	expected := []string{"Login is needed", "must have required property 'email'", "must be integer"}
+	if !reflect.DeepEqual(messages, expected) {
+		t.Errorf("got %v, want %v", messages, expected)
+	}
+
+	errs = schema.Validate(s, map[string]any{"login": "ab", "email": "x"})
+	if len(errs) != 2 || errs[0].Message != "Enter a valid email" || errs[1].Message != "At least 3 characters" {
+		t.Errorf("unexpected custom messages %v", errs)
+	}
+}
+
+func TestValidate_OneOf(t *testing.T) {
+	s := &schema.JSONSchema{OneOf: []*schema.JSONSchema{
+		{Type: "string"},
+		{Type: "integer"},
+		{Type: "number"},
+	}}
+
+	if errs := schema.Validate(s, "x"); errs != nil {
+		t.Errorf("expected a single matching branch, got %v", errs)
+	}
+
+	// 3 is both an integer and a number.
+	if errs := schema.Validate(s, 3); len(errs) != 1 || errs[0].Params["passingSchemas"] != 2 {
+		t.Errorf("expected oneOf error, got %v", errs)
+	}
+}
+
+func TestValidate_EnumNumbersAndBase64(t *testing.T) {
+	s := &schema.JSONSchema{
+		Type: "object",
+		Properties: map[string]*schema.JSONSchema{
+			"level": {Type: "integer", Enum: []any{1, 2, 3}},
+			"blob":  {Type: "string", ContentEncoding: "base64"},
+		},
+	}
+
+	if errs := schema.Validate(s, `{"level": 2.0, "blob": "aGk="}`); len(errs) != 1 || errs[0].Keyword != "type" {
+		t.Errorf("expected a Go string to be validated as a JSON string, got %v", errs)
+	}
+
+	if errs := schema.Validate(s, json.RawMessage(`{"level": 2.0, "blob": "aGk="}`)); errs != nil {
+		t.Errorf("expected no errors, got %v", errs)
+	}
+
+	errs := schema.Validate(s, json.RawMessage(`{"level": 4, "blob": "!"}`))
+	if len(errs) != 2 || errs[0].Keyword != "contentEncoding" || errs[1].Keyword != "enum" {
+		t.Errorf("expected contentEncoding and enum errors, got %v", errs)
+	}
+}
+
+func TestValidFormat(t *testing.T) {
+	tests := []struct {
+		format, value string
+		valid         bool
+	}{
+		{"date-time", "2024-01-31T10:00:00Z", true},
+		{"date-time", "2024-01-31t10:00:00.5+02:00", true},
+		{"date-time", "2024-01-31", false},
+		{"date", "2024-02-29", true},
+		{"date", "2023-02-29", false},
+		{"time", "14:30:00Z", true},
+		{"time", "14:30", false},
+		{"email", "a.b@example.com", true},
+		{"email", "John <j@example.com>", false},
+		{"hostname", "api.example.com", true},
+		{"hostname", "-bad.example.com", false},
+		{"ipv4", "192.168.0.1", true},
+		{"ipv4", "::1", false},
+		{"ipv6", "::1", true},
+		{"ipv6", "10.0.0.1", false},
+		{"uri", "https://example.com/a?b=c", true},
+		{"uri", "/relative", false},
+		{"uuid", "123e4567-e89b-12d3-a456-426614174000", true},
+		{"uuid", "123e4567", false},
+		{"regex", "^a+$", true},
+		{"regex", "(", false},
+		{"currency", "anything", true},
+	}
+
+	for _, tt := range tests {
+		if got := schema.ValidFormat(tt.format, tt.value); got != tt.valid {
+			t.Errorf("ValidFormat(%q, %q) = %v, want %v", tt.format, tt.value, got, tt.valid)
+		}
+	}
+}
+
+func TestValidate_NullableAndInvalidPattern(t *testing.T) {
+	s := &schema.JSONSchema{
+		Type: "object",
+		Properties: map[string]*schema.JSONSchema{
+			"tags": {Type: "array", Items: &schema.JSONSchema{Type: "string"}, Nullable: true, MinItems: intPtr(1)},
+			"name": {Type: "string"},
+			"ref":  {Type: "string", Pattern: `^(?=x)`},
+		},
+	}
+
+	if errs := schema.Validate(s, json.RawMessage(`{"tags": null}`)); errs != nil {
+		t.Errorf("expected null to satisfy a nullable schema, got %v", errs)
+	}
+
+	errs := schema.Validate(s, json.RawMessage(`{"name": null, "tags": "x"}`))
+	if len(errs) != 2 || errs[0].Keyword != "type" || errs[1].Keyword != "type" {
+		t.Errorf("expected type errors for null name and string tags, got %v", errs)
+	}
+
+	errs = schema.Validate(s, json.RawMessage(`{"ref": "x"}`))
+	if len(errs) != 1 || errs[0].Keyword != "pattern" || !strings.Contains(errs[0].Message, "cannot be checked") {
+		t.Errorf("expected an unsupported pattern to be reported, got %v", errs)
+	}
+}